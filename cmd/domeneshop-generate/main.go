@@ -0,0 +1,120 @@
+// Command domeneshop-generate writes Terraform configuration with import
+// blocks for the domains, DNS records and HTTP forwards of an existing
+// Domeneshop account, to adopt them with Terraform 1.5 or later.
+//
+// Credentials are read from DOMENESHOP_TOKEN and DOMENESHOP_SECRET, and the
+// API base URL from DOMENESHOP_ENDPOINT, like the provider does.
+//
+// Usage:
+//
+//	domeneshop-generate [-domain example.com,example.org] [-out dir]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop"
+	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/generate"
+	"terraform-provider-domeneshop/domeneshop/idn"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+func main() {
+	domains := flag.String("domain", "", "comma separated domains to generate configuration for, defaults to all")
+	out := flag.String("out", "", "directory to write one <domain>.tf file per domain to, defaults to stdout")
+	flag.Parse()
+
+	if err := run(context.Background(), *domains, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "domeneshop-generate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, domains, out string) error {
+	token, secret := os.Getenv("DOMENESHOP_TOKEN"), os.Getenv("DOMENESHOP_SECRET")
+	if token == "" || secret == "" {
+		return fmt.Errorf("DOMENESHOP_TOKEN and DOMENESHOP_SECRET must be set")
+	}
+
+	endpoint, err := api.NewEndpoint(os.Getenv("DOMENESHOP_ENDPOINT"))
+	if err != nil {
+		return fmt.Errorf("DOMENESHOP_ENDPOINT: %w", err)
+	}
+
+	config := domeneshop.DefaultHTTPClientConfig
+	config.Token, config.Secret = token, secret
+	apiClient := client.New(domeneshop.NewHTTPClient(config), endpoint)
+
+	selected, err := selectDomains(ctx, apiClient, domains)
+	if err != nil {
+		return err
+	}
+
+	generator := generate.New(apiClient)
+	for _, domain := range selected {
+		if err := writeDomain(ctx, generator, domain, out); err != nil {
+			return fmt.Errorf("%s: %w", domain.Domain, err)
+		}
+	}
+
+	return nil
+}
+
+// selectDomains returns the domains named in the comma separated list, or all
+// domains of the account when it is empty.
+func selectDomains(ctx context.Context, apiClient client.API, names string) ([]model.Domain, error) {
+	all, err := apiClient.ListDomains(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	if names == "" {
+		return all, nil
+	}
+
+	byName := map[string]model.Domain{}
+	for _, domain := range all {
+		byName[idn.Normalize(domain.Domain)] = domain
+	}
+
+	var selected []model.Domain
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		domain, ok := byName[idn.Normalize(name)]
+		if !ok {
+			return nil, fmt.Errorf("domain %s not found in the account", name)
+		}
+		selected = append(selected, domain)
+	}
+
+	return selected, nil
+}
+
+func writeDomain(ctx context.Context, generator *generate.Generator, domain model.Domain, out string) error {
+	if out == "" {
+		return generator.Domain(ctx, domain, os.Stdout)
+	}
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(out, domain.Domain+".tf"))
+	if err != nil {
+		return err
+	}
+
+	err = generator.Domain(ctx, domain, file)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
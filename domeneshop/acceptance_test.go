@@ -0,0 +1,261 @@
+package domeneshop_test
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"os"
+	"regexp"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"testing"
+)
+
+// The acceptance tests run the provider against an in-process fake of the
+// API. helper/resource is not part of the vendored SDK, so testAccResource
+// drives the same validate, plan, apply, refresh and destroy cycle through
+// the schema.Resource shims directly.
+
+type testAccStep struct {
+	// PreConfig is called before the step, i.e. to change things behind
+	// Terraform's back.
+	PreConfig func()
+	// Config is the resource configuration for this step.
+	Config map[string]interface{}
+	// ExpectError, when set, must match the error from validation, plan or
+	// apply, and the step ends there.
+	ExpectError *regexp.Regexp
+	// Check is called with the refreshed state after apply.
+	Check func(state *terraform.InstanceState) error
+
+	// ImportState makes this an import step: the resource is imported and
+	// compared to the current state.
+	ImportState bool
+	// ImportStateId is the id to import, defaults to the id in state.
+	ImportStateId string
+	// ImportStateVerifyIgnore lists attribute prefixes not compared on import.
+	ImportStateVerifyIgnore []string
+}
+
+func testAccPreCheck(t *testing.T) {
+	if os.Getenv("TF_ACC") == "" {
+		t.Skip("Acceptance tests skipped unless env 'TF_ACC' set")
+	}
+}
+
+// testAccProvider returns a provider configured against server.
+func testAccProvider(t *testing.T, server *fake.Server) *schema.Provider {
+	provider := domeneshop.Provider()
+
+	diags := provider.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]interface{}{
+		"token":    "token",
+		"secret":   "secret",
+		"endpoint": server.URL,
+		// The fake doesn't throttle.
+		"requests_per_second": 0,
+	}))
+	if diags.HasError() {
+		t.Fatalf("configuring provider: %v", diagError(diags))
+	}
+
+	return provider
+}
+
+// testAccResource applies each step in order, then destroys the resource and
+// calls checkDestroy.
+func testAccResource(t *testing.T, provider *schema.Provider, resourceType string, steps []testAccStep, checkDestroy func() error) {
+	ctx := context.Background()
+	resource := provider.ResourcesMap[resourceType]
+	meta := provider.Meta()
+
+	var state *terraform.InstanceState
+
+	for i, step := range steps {
+		// Terraform starts the provider afresh for each command, so nothing
+		// is cached from one step to the next.
+		if cache, ok := meta.(*client.Cache); ok {
+			cache.Reset()
+		}
+
+		if step.ImportState {
+			testAccImport(t, provider, resourceType, state, step)
+			continue
+		}
+
+		if step.PreConfig != nil {
+			step.PreConfig()
+		}
+
+		config := terraform.NewResourceConfigRaw(step.Config)
+
+		var err error
+		if state != nil {
+			state, err = testAccRefresh(ctx, resource, state, meta)
+			if err != nil {
+				t.Fatalf("step %d: refresh: %v", i, err)
+			}
+		}
+
+		err = diagError(resource.Validate(config))
+		if err == nil {
+			var diff *terraform.InstanceDiff
+			diff, err = resource.Diff(ctx, state, config, meta)
+			if err == nil && diff != nil && !diff.Empty() {
+				var diags diag.Diagnostics
+				var applied *terraform.InstanceState
+				applied, diags = resource.Apply(ctx, state, diff, meta)
+				if applied != nil && applied.ID != "" {
+					state = applied
+				}
+				err = diagError(diags)
+			}
+		}
+
+		if step.ExpectError != nil {
+			if err == nil {
+				t.Fatalf("step %d: expected an error matching %s, got none", i, step.ExpectError)
+			}
+			if !step.ExpectError.MatchString(err.Error()) {
+				t.Fatalf("step %d: expected an error matching %s, got: %v", i, step.ExpectError, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+
+		state, err = testAccRefresh(ctx, resource, state, meta)
+		if err != nil {
+			t.Fatalf("step %d: refresh: %v", i, err)
+		}
+		if state == nil {
+			t.Fatalf("step %d: resource is gone after apply", i)
+		}
+
+		if step.Check != nil {
+			if err := step.Check(state); err != nil {
+				t.Fatalf("step %d: check: %v", i, err)
+			}
+		}
+
+		diff, err := resource.Diff(ctx, state, config, meta)
+		if err != nil {
+			t.Fatalf("step %d: plan after apply: %v", i, err)
+		}
+		if diff != nil && !diff.Empty() {
+			t.Fatalf("step %d: after applying this step, the plan was not empty:\n%#v", i, diff.Attributes)
+		}
+	}
+
+	if state != nil {
+		_, diags := resource.Apply(ctx, state, &terraform.InstanceDiff{Destroy: true}, meta)
+		if err := diagError(diags); err != nil {
+			t.Fatalf("destroy: %v", err)
+		}
+	}
+
+	if checkDestroy != nil {
+		if err := checkDestroy(); err != nil {
+			t.Fatalf("check destroy: %v", err)
+		}
+	}
+}
+
+func testAccImport(t *testing.T, provider *schema.Provider, resourceType string, state *terraform.InstanceState, step testAccStep) {
+	ctx := context.Background()
+	resource := provider.ResourcesMap[resourceType]
+
+	if step.ImportStateId == "" {
+		step.ImportStateId = state.ID
+	}
+
+	imported, err := provider.ImportState(ctx, &terraform.InstanceInfo{Type: resourceType}, step.ImportStateId)
+	if err != nil {
+		t.Fatalf("import %s: %v", step.ImportStateId, err)
+	}
+	if len(imported) != 1 {
+		t.Fatalf("import %s: expected 1 resource, got %d", step.ImportStateId, len(imported))
+	}
+
+	refreshed, err := testAccRefresh(ctx, resource, imported[0], provider.Meta())
+	if err != nil {
+		t.Fatalf("import %s: refresh: %v", step.ImportStateId, err)
+	}
+	if refreshed == nil {
+		t.Fatalf("import %s: resource not found", step.ImportStateId)
+	}
+
+	ignored := func(key string) bool {
+		for _, prefix := range step.ImportStateVerifyIgnore {
+			if strings.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for key, expected := range state.Attributes {
+		if ignored(key) {
+			continue
+		}
+		if actual := refreshed.Attributes[key]; actual != expected {
+			t.Errorf("import %s: attribute %s: expected %q, got %q", step.ImportStateId, key, expected, actual)
+		}
+	}
+	if refreshed.ID != state.ID {
+		t.Errorf("import %s: expected id %q, got %q", step.ImportStateId, state.ID, refreshed.ID)
+	}
+}
+
+func testAccRefresh(ctx context.Context, resource *schema.Resource, state *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	refreshed, diags := resource.RefreshWithoutUpgrade(ctx, state, meta)
+	return refreshed, diagError(diags)
+}
+
+// testAccDataSource reads a data source with the given configuration.
+func testAccDataSource(t *testing.T, provider *schema.Provider, dataSourceType string, raw map[string]interface{}) (*terraform.InstanceState, error) {
+	ctx := context.Background()
+	resource := provider.DataSourcesMap[dataSourceType]
+	config := terraform.NewResourceConfigRaw(raw)
+
+	if err := diagError(resource.Validate(config)); err != nil {
+		return nil, err
+	}
+
+	diff, err := resource.Diff(ctx, nil, config, provider.Meta())
+	if err != nil {
+		return nil, err
+	}
+
+	state, diags := resource.ReadDataApply(ctx, diff, provider.Meta())
+	return state, diagError(diags)
+}
+
+type diagErrors diag.Diagnostics
+
+func (d diagErrors) Error() string {
+	var message string
+	for _, diagnostic := range d {
+		if diagnostic.Severity != diag.Error {
+			continue
+		}
+		if message != "" {
+			message += "; "
+		}
+		message += diagnostic.Summary
+		if diagnostic.Detail != "" {
+			message += ": " + diagnostic.Detail
+		}
+	}
+	return message
+}
+
+func diagError(diags diag.Diagnostics) error {
+	if !diags.HasError() {
+		return nil
+	}
+	return diagErrors(diags)
+}
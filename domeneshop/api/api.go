@@ -1,23 +1,67 @@
 package api
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 const (
-	apiURL = "https://api.domeneshop.no/v0"
+	DefaultURL = "https://api.domeneshop.no/v0"
 )
 
-func DNSRecords(domainId int) string {
-	return fmt.Sprintf("%s/dns", Domain(domainId))
+// Endpoint is the base URL of the Domeneshop API, i.e. DefaultURL.
+type Endpoint string
+
+// NewEndpoint returns the Endpoint for baseURL, or DefaultURL when empty.
+func NewEndpoint(baseURL string) (Endpoint, error) {
+	if baseURL == "" {
+		return DefaultURL, nil
+	}
+
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("parsing endpoint: %w", err)
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return "", fmt.Errorf("endpoint must be an http or https URL, got %q", baseURL)
+	}
+
+	return Endpoint(strings.TrimSuffix(baseURL, "/")), nil
+}
+
+func (e Endpoint) DNSRecords(domainId int) string {
+	return fmt.Sprintf("%s/dns", e.Domain(domainId))
+}
+
+func (e Endpoint) DNSRecord(domainId, recordId int) string {
+	return fmt.Sprintf("%s/%d", e.DNSRecords(domainId), recordId)
+}
+
+func (e Endpoint) Domains() string {
+	return fmt.Sprintf("%s/domains", e)
+}
+
+func (e Endpoint) Domain(domainId int) string {
+	return fmt.Sprintf("%s/%d", e.Domains(), domainId)
+}
+
+func (e Endpoint) Forwards(domainId int) string {
+	return fmt.Sprintf("%s/forwards/", e.Domain(domainId))
+}
+
+func (e Endpoint) Forward(domainId int, host string) string {
+	return fmt.Sprintf("%s%s", e.Forwards(domainId), url.PathEscape(host))
 }
 
-func DNSRecord(domainId, recordId int) string {
-	return fmt.Sprintf("%s/%d", DNSRecords(domainId), recordId)
+func (e Endpoint) Invoices() string {
+	return fmt.Sprintf("%s/invoices", e)
 }
 
-func Domains() string {
-	return fmt.Sprintf("%s/domains", apiURL)
+func (e Endpoint) Invoice(invoiceId int) string {
+	return fmt.Sprintf("%s/%d", e.Invoices(), invoiceId)
 }
 
-func Domain(domainId int) string {
-	return fmt.Sprintf("%s/%d", Domains(), domainId)
+func (e Endpoint) DynDNSUpdate() string {
+	return fmt.Sprintf("%s/dyndns/update", e)
 }
@@ -0,0 +1,145 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"time"
+)
+
+// Cache is an API reading DNS records through a short-lived cache of the
+// records of each domain, so refreshing many records of a domain costs a
+// single request. Concurrent reads of a domain share one request, and writes
+// to a domain drop its cached records.
+type Cache struct {
+	API
+	ttl     time.Duration
+	timeout time.Duration
+
+	mu      sync.Mutex
+	records map[int]*recordsEntry
+}
+
+type recordsEntry struct {
+	done    chan struct{}
+	fetched time.Time
+	records []model.DnsRecord
+	err     error
+}
+
+// NewCache returns a Cache over api keeping the records of a domain for ttl.
+// A shared request for the records of a domain is given up after timeout, or
+// never if it is 0.
+func NewCache(api API, ttl, timeout time.Duration) *Cache {
+	return &Cache{
+		API:     api,
+		ttl:     ttl,
+		timeout: timeout,
+		records: map[int]*recordsEntry{},
+	}
+}
+
+// GetRecord returns the record from the cached records of the domain. A record
+// not among them is requested directly, in case it was created since.
+func (c *Cache) GetRecord(ctx context.Context, domainId, recordId int) (*model.DnsRecord, error) {
+	records, err := c.domainRecords(ctx, domainId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		if record.Id == recordId {
+			return &record, nil
+		}
+	}
+
+	return c.API.GetRecord(ctx, domainId, recordId)
+}
+
+func (c *Cache) CreateRecord(ctx context.Context, domainId int, record *model.DnsRecord) (int, error) {
+	defer c.invalidate(domainId)
+	return c.API.CreateRecord(ctx, domainId, record)
+}
+
+func (c *Cache) UpdateRecord(ctx context.Context, domainId int, record *model.DnsRecord) error {
+	defer c.invalidate(domainId)
+	return c.API.UpdateRecord(ctx, domainId, record)
+}
+
+func (c *Cache) DeleteRecord(ctx context.Context, domainId, recordId int) error {
+	defer c.invalidate(domainId)
+	return c.API.DeleteRecord(ctx, domainId, recordId)
+}
+
+// DynDNSUpdate drops the cached records of every domain, as the hostname
+// doesn't tell which domain the updated record belongs to.
+func (c *Cache) DynDNSUpdate(ctx context.Context, hostname, myip string) error {
+	defer c.Reset()
+	return c.API.DynDNSUpdate(ctx, hostname, myip)
+}
+
+// Reset drops the cached records of every domain.
+func (c *Cache) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.records = map[int]*recordsEntry{}
+}
+
+// domainRecords returns the records of the domain, from the cache while they
+// are fresh, or from the request in flight for them.
+func (c *Cache) domainRecords(ctx context.Context, domainId int) ([]model.DnsRecord, error) {
+	c.mu.Lock()
+	entry, ok := c.records[domainId]
+	if ok && !entry.fetched.IsZero() && time.Since(entry.fetched) > c.ttl {
+		delete(c.records, domainId)
+		ok = false
+	}
+	if !ok {
+		entry = &recordsEntry{done: make(chan struct{})}
+		c.records[domainId] = entry
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		go c.fetch(domainId, entry)
+	}
+
+	select {
+	case <-entry.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	return entry.records, entry.err
+}
+
+// fetch lists the records of the domain into entry. The request is shared by
+// every caller waiting for entry, so it doesn't run under the context of any
+// of them, which could be cancelled while the others still wait.
+func (c *Cache) fetch(domainId int, entry *recordsEntry) {
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if c.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+	}
+	defer cancel()
+
+	records, err := c.API.ListRecords(ctx, domainId, "", "")
+
+	c.mu.Lock()
+	entry.records, entry.err = records, err
+	entry.fetched = time.Now()
+	if err != nil && c.records[domainId] == entry {
+		delete(c.records, domainId)
+	}
+	c.mu.Unlock()
+
+	close(entry.done)
+}
+
+func (c *Cache) invalidate(domainId int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.records, domainId)
+}
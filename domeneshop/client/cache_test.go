@@ -0,0 +1,129 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	var mu sync.Mutex
+	requests := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/domains/1/dns":
+			// Give concurrent reads time to pile up.
+			time.Sleep(10 * time.Millisecond)
+			_, _ = w.Write([]byte(`[{"id": 2, "host": "www", "type": "A", "data": "192.0.2.1"}, {"id": 3, "host": "@", "type": "A", "data": "192.0.2.2"}]`))
+		case r.Method == "PUT" || r.URL.Path == "/dyndns/update":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c := client.NewCache(client.New(server.Client(), api.Endpoint(server.URL)), time.Minute, time.Minute)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(recordId int) {
+			defer wg.Done()
+			record, err := c.GetRecord(ctx, 1, recordId)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if record.Id != recordId {
+				t.Errorf("expected record %d, got %+v", recordId, record)
+			}
+		}(2 + i%2)
+	}
+	wg.Wait()
+
+	if n := requests["GET /domains/1/dns"]; n != 1 {
+		t.Errorf("expected the records to be listed once, listed %d times", n)
+	}
+
+	// Records missing from the list are requested directly.
+	if _, err := c.GetRecord(ctx, 1, 4); !client.IsNotFound(err) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if n := requests["GET /domains/1/dns/4"]; n != 1 {
+		t.Errorf("expected record 4 to be requested once, requested %d times", n)
+	}
+
+	// Writes drop the cached records.
+	if err := c.UpdateRecord(ctx, 1, &model.DnsRecord{Id: 2, Host: "www", Type: "A", Data: "192.0.2.3"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRecord(ctx, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests["GET /domains/1/dns"]; n != 2 {
+		t.Errorf("expected the records to be listed again after a write, listed %d times", n)
+	}
+
+	// So do dynamic DNS updates, of any domain.
+	if err := c.DynDNSUpdate(ctx, "www.example.com", "192.0.2.4"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetRecord(ctx, 1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if n := requests["GET /domains/1/dns"]; n != 3 {
+		t.Errorf("expected the records to be listed again after a dynamic DNS update, listed %d times", n)
+	}
+}
+
+func TestCache_cancel(t *testing.T) {
+	listing := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(listing)
+		<-release
+		_, _ = w.Write([]byte(`[{"id": 2, "host": "www", "type": "A", "data": "192.0.2.1"}]`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	c := client.NewCache(client.New(server.Client(), api.Endpoint(server.URL)), time.Minute, time.Minute)
+
+	// The first read starts the shared request, and gives up while it runs.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error)
+	go func() {
+		_, err := c.GetRecord(ctx, 1, 2)
+		first <- err
+	}()
+	<-listing
+
+	second := make(chan error)
+	go func() {
+		_, err := c.GetRecord(context.Background(), 1, 2)
+		second <- err
+	}()
+
+	cancel()
+	if err := <-first; err != context.Canceled {
+		t.Errorf("expected the first read to be cancelled, got %v", err)
+	}
+
+	// The second read still gets the records of the shared request.
+	release <- struct{}{}
+	if err := <-second; err != nil {
+		t.Errorf("expected the second read to get the record, got %v", err)
+	}
+}
@@ -0,0 +1,314 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// API is the set of Domeneshop API operations the provider depends on.
+type API interface {
+	ListDomains(ctx context.Context, filter string) ([]model.Domain, error)
+	GetDomain(ctx context.Context, domainId int) (*model.Domain, error)
+
+	ListRecords(ctx context.Context, domainId int, host, recordType string) ([]model.DnsRecord, error)
+	GetRecord(ctx context.Context, domainId, recordId int) (*model.DnsRecord, error)
+	CreateRecord(ctx context.Context, domainId int, record *model.DnsRecord) (int, error)
+	UpdateRecord(ctx context.Context, domainId int, record *model.DnsRecord) error
+	DeleteRecord(ctx context.Context, domainId, recordId int) error
+
+	ListForwards(ctx context.Context, domainId int) ([]model.HttpForward, error)
+	GetForward(ctx context.Context, domainId int, host string) (*model.HttpForward, error)
+	CreateForward(ctx context.Context, domainId int, forward *model.HttpForward) error
+	UpdateForward(ctx context.Context, domainId int, forward *model.HttpForward) error
+	DeleteForward(ctx context.Context, domainId int, host string) error
+
+	ListInvoices(ctx context.Context, status string) ([]model.Invoice, error)
+	GetInvoice(ctx context.Context, invoiceId int) (*model.Invoice, error)
+
+	DynDNSUpdate(ctx context.Context, hostname, myip string) error
+}
+
+// Client implements API over HTTP.
+type Client struct {
+	http     *http.Client
+	endpoint api.Endpoint
+}
+
+// New returns a Client sending requests through httpClient, which is expected
+// to add authentication, to the API at endpoint.
+func New(httpClient *http.Client, endpoint api.Endpoint) *Client {
+	return &Client{
+		http:     httpClient,
+		endpoint: endpoint,
+	}
+}
+
+// Error is returned when the API responds with an unexpected status code.
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s %s: unexpected status code %d. Response : %v", e.Method, e.URL, e.StatusCode, e.Body)
+}
+
+// IsNotFound reports whether err is, or wraps, an *Error for a 404 response.
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+func (c *Client) ListDomains(ctx context.Context, filter string) ([]model.Domain, error) {
+	query := url.Values{}
+	if filter != "" {
+		query.Set("domain", filter)
+	}
+
+	var domains []model.Domain
+	err := c.do(ctx, "GET", withQuery(c.endpoint.Domains(), query), nil, &domains, 200)
+	if err != nil {
+		return nil, fmt.Errorf("listing domains: %w", err)
+	}
+
+	return domains, nil
+}
+
+func (c *Client) GetDomain(ctx context.Context, domainId int) (*model.Domain, error) {
+	var domain model.Domain
+	err := c.do(ctx, "GET", c.endpoint.Domain(domainId), nil, &domain, 200)
+	if err != nil {
+		return nil, fmt.Errorf("getting domain %d: %w", domainId, err)
+	}
+
+	return &domain, nil
+}
+
+func (c *Client) ListRecords(ctx context.Context, domainId int, host, recordType string) ([]model.DnsRecord, error) {
+	query := url.Values{}
+	if host != "" {
+		query.Set("host", host)
+	}
+	if recordType != "" {
+		query.Set("type", recordType)
+	}
+
+	var records []model.DnsRecord
+	err := c.do(ctx, "GET", withQuery(c.endpoint.DNSRecords(domainId), query), nil, &records, 200)
+	if err != nil {
+		return nil, fmt.Errorf("listing DNS records of domain %d: %w", domainId, err)
+	}
+
+	return records, nil
+}
+
+func (c *Client) GetRecord(ctx context.Context, domainId, recordId int) (*model.DnsRecord, error) {
+	var record model.DnsRecord
+	err := c.do(ctx, "GET", c.endpoint.DNSRecord(domainId, recordId), nil, &record, 200)
+	if err != nil {
+		return nil, fmt.Errorf("getting DNS record %d/%d: %w", domainId, recordId, err)
+	}
+
+	return &record, nil
+}
+
+func (c *Client) CreateRecord(ctx context.Context, domainId int, record *model.DnsRecord) (int, error) {
+	var created model.InlineResponse201
+	err := c.do(ctx, "POST", c.endpoint.DNSRecords(domainId), record, &created, 201)
+	if err != nil {
+		return 0, fmt.Errorf("creating DNS record in domain %d: %w", domainId, err)
+	}
+
+	return created.Id, nil
+}
+
+func (c *Client) UpdateRecord(ctx context.Context, domainId int, record *model.DnsRecord) error {
+	err := c.do(ctx, "PUT", c.endpoint.DNSRecord(domainId, record.Id), record, nil, 204)
+	if err != nil {
+		return fmt.Errorf("updating DNS record %d/%d: %w", domainId, record.Id, err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteRecord(ctx context.Context, domainId, recordId int) error {
+	err := c.do(ctx, "DELETE", c.endpoint.DNSRecord(domainId, recordId), nil, nil, 204)
+	if err != nil {
+		return fmt.Errorf("deleting DNS record %d/%d: %w", domainId, recordId, err)
+	}
+
+	return nil
+}
+
+func (c *Client) ListForwards(ctx context.Context, domainId int) ([]model.HttpForward, error) {
+	var forwards []model.HttpForward
+	err := c.do(ctx, "GET", c.endpoint.Forwards(domainId), nil, &forwards, 200)
+	if err != nil {
+		return nil, fmt.Errorf("listing forwards of domain %d: %w", domainId, err)
+	}
+
+	return forwards, nil
+}
+
+func (c *Client) GetForward(ctx context.Context, domainId int, host string) (*model.HttpForward, error) {
+	var forward model.HttpForward
+	err := c.do(ctx, "GET", c.endpoint.Forward(domainId, host), nil, &forward, 200)
+	if err != nil {
+		return nil, fmt.Errorf("getting forward %d/%s: %w", domainId, host, err)
+	}
+
+	return &forward, nil
+}
+
+func (c *Client) CreateForward(ctx context.Context, domainId int, forward *model.HttpForward) error {
+	err := c.do(ctx, "POST", c.endpoint.Forwards(domainId), forward, nil, 201)
+	if err != nil {
+		return fmt.Errorf("creating forward %d/%s: %w", domainId, forward.Host, err)
+	}
+
+	return nil
+}
+
+func (c *Client) UpdateForward(ctx context.Context, domainId int, forward *model.HttpForward) error {
+	err := c.do(ctx, "PUT", c.endpoint.Forward(domainId, forward.Host), forward, nil, 200, 204)
+	if err != nil {
+		return fmt.Errorf("updating forward %d/%s: %w", domainId, forward.Host, err)
+	}
+
+	return nil
+}
+
+func (c *Client) DeleteForward(ctx context.Context, domainId int, host string) error {
+	err := c.do(ctx, "DELETE", c.endpoint.Forward(domainId, host), nil, nil, 204)
+	if err != nil {
+		return fmt.Errorf("deleting forward %d/%s: %w", domainId, host, err)
+	}
+
+	return nil
+}
+
+func (c *Client) ListInvoices(ctx context.Context, status string) ([]model.Invoice, error) {
+	query := url.Values{}
+	if status != "" {
+		query.Set("status", status)
+	}
+
+	var invoices []model.Invoice
+	err := c.do(ctx, "GET", withQuery(c.endpoint.Invoices(), query), nil, &invoices, 200)
+	if err != nil {
+		return nil, fmt.Errorf("listing invoices: %w", err)
+	}
+
+	return invoices, nil
+}
+
+func (c *Client) GetInvoice(ctx context.Context, invoiceId int) (*model.Invoice, error) {
+	var invoice model.Invoice
+	err := c.do(ctx, "GET", c.endpoint.Invoice(invoiceId), nil, &invoice, 200)
+	if err != nil {
+		return nil, fmt.Errorf("getting invoice %d: %w", invoiceId, err)
+	}
+
+	return &invoice, nil
+}
+
+// DynDNSUpdate creates or updates the A or AAAA record of hostname. An empty
+// myip lets the API use the address the request comes from.
+func (c *Client) DynDNSUpdate(ctx context.Context, hostname, myip string) error {
+	query := url.Values{"hostname": {hostname}}
+	if myip != "" {
+		query.Set("myip", myip)
+	}
+
+	err := c.do(ctx, "GET", withQuery(c.endpoint.DynDNSUpdate(), query), nil, nil, 204)
+	if err != nil {
+		return fmt.Errorf("dynamic DNS update of %s: %w", hostname, err)
+	}
+
+	return nil
+}
+
+// do sends a request with in encoded as the JSON body, and decodes the
+// response into out. Any status code not in expected results in an *Error.
+func (c *Client) do(ctx context.Context, method, uri string, in, out interface{}, expected ...int) error {
+	var body io.Reader
+	if in != nil {
+		buffer := new(bytes.Buffer)
+		if err := json.NewEncoder(buffer).Encode(in); err != nil {
+			return fmt.Errorf("encoding request: %w", err)
+		}
+		body = buffer
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, uri, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("HTTP %s: %w", method, err)
+	}
+	defer closeBody(response.Body)
+
+	if !contains(expected, response.StatusCode) {
+		b, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return err
+		}
+		return &Error{
+			Method:     method,
+			URL:        uri,
+			StatusCode: response.StatusCode,
+			Body:       string(b),
+		}
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	return nil
+}
+
+func withQuery(uri string, query url.Values) string {
+	if len(query) == 0 {
+		return uri
+	}
+	return fmt.Sprintf("%s?%s", uri, query.Encode())
+}
+
+func contains(haystack []int, needle int) bool {
+	for _, value := range haystack {
+		if value == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func closeBody(body io.ReadCloser) {
+	if err := body.Close(); err != nil {
+		log.Printf("closing body: %v\n", err)
+	}
+}
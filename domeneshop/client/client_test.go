@@ -0,0 +1,57 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"testing"
+)
+
+func TestClientError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "record:not_found"}`))
+	}))
+	defer server.Close()
+
+	c := client.New(server.Client(), api.Endpoint(server.URL))
+
+	_, err := c.GetRecord(context.Background(), 1, 2)
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *client.Error, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status code 404, got %d", apiErr.StatusCode)
+	}
+	if apiErr.Body != `{"code": "record:not_found"}` {
+		t.Errorf("unexpected body %q", apiErr.Body)
+	}
+}
+
+func TestClientListRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/domains/1/dns" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if r.URL.Query().Get("type") != "MX" {
+			t.Errorf("expected type filter MX, got %q", r.URL.Query().Get("type"))
+		}
+		_, _ = w.Write([]byte(`[{"id": 2, "host": "@", "type": "MX", "data": "mx.example.com", "priority": "10"}]`))
+	}))
+	defer server.Close()
+
+	c := client.New(server.Client(), api.Endpoint(server.URL))
+
+	records, err := c.ListRecords(context.Background(), 1, "", "MX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Id != 2 || records[0].Priority != "10" {
+		t.Errorf("unexpected records %+v", records)
+	}
+}
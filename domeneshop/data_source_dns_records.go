@@ -0,0 +1,92 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"terraform-provider-domeneshop/domeneshop/client"
+)
+
+func dataSourceDNSRecords() *schema.Resource {
+	record := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ttl": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+	for key, field := range dnsRecordFieldsSchema() {
+		record.Schema[key] = &schema.Schema{
+			Type:     field.Type,
+			Computed: true,
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceDNSRecordsRead,
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"records": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     record,
+			},
+		},
+	}
+}
+
+func dataSourceDNSRecordsRead(ctx context.Context, d *schema.ResourceData, i interface{}) diag.Diagnostics {
+	client := i.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+	host := d.Get("host").(string)
+	recordType := d.Get("type").(string)
+
+	records, err := client.ListRecords(ctx, domainId, host, recordType)
+	if err != nil {
+		return apiError("reading DNS records", err)
+	}
+
+	flattened := make([]map[string]interface{}, 0, len(records))
+	for _, record := range records {
+		fields := flattenDNSRecordFields(&record)
+		fields["id"] = record.Id
+		fields["host"] = record.Host
+		fields["type"] = record.Type
+		fields["ttl"] = record.Ttl
+		flattened = append(flattened, fields)
+	}
+
+	if err := d.Set("records", flattened); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%d/%s/%s", domainId, host, recordType))
+
+	return nil
+}
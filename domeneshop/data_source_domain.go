@@ -2,14 +2,15 @@ package domeneshop
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"github.com/VegarM/domeneshop-go"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"net/http"
+	"sort"
 	"strconv"
-	"terraform-provider-domeneshop/domeneshop/api"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/idn"
+	"terraform-provider-domeneshop/domeneshop/model"
 )
 
 func dataSourceDomain() *schema.Resource {
@@ -17,16 +18,20 @@ func dataSourceDomain() *schema.Resource {
 		ReadContext: dataSourceDomainRead,
 		Schema: map[string]*schema.Schema{
 			"domain": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"domain", "id"},
 			},
 			"expiry_date": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 			"id": {
-				Type:     schema.TypeInt,
-				Computed: true,
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"domain", "id"},
 			},
 			"nameservers": {
 				Type:     schema.TypeList,
@@ -72,67 +77,164 @@ func dataSourceDomain() *schema.Resource {
 }
 
 func dataSourceDomainRead(ctx context.Context, d *schema.ResourceData, i interface{}) diag.Diagnostics {
+	client := i.(client.API)
+
+	var domain *model.Domain
 	var diags diag.Diagnostics
-	client := i.(*http.Client)
+	if id, ok := d.GetOk("id"); ok {
+		domain, diags = getDomainById(ctx, client, id.(int))
+	} else {
+		domain, diags = getDomainByName(ctx, client, d.Get("domain").(string))
+	}
+	if diags.HasError() {
+		return diags
+	}
 
-	name := d.Get("domain").(string)
+	diags = append(diags, setDomainData(domain, d)...)
+	d.SetId(strconv.Itoa(domain.Id))
 
-	domains, err := getDomains(client)
+	return diags
+}
+
+func getDomainById(ctx context.Context, api client.API, id int) (*model.Domain, diag.Diagnostics) {
+	domain, err := api.GetDomain(ctx, id)
+	if client.IsNotFound(err) {
+		return nil, diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  "Domain not found",
+			Detail:   fmt.Sprintf("No domain with id %d on the account.", id),
+		}}
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, apiError("reading domain", err)
 	}
 
+	return domain, nil
+}
+
+// getDomainByName returns the domain named name, ignoring case and a trailing
+// dot, and comparing internationalized names in their punycode form. When
+// there is no such domain, the error suggests similarly named ones.
+func getDomainByName(ctx context.Context, api client.API, name string) (*model.Domain, diag.Diagnostics) {
+	domains, err := api.ListDomains(ctx, "")
+	if err != nil {
+		return nil, apiError("reading domains", err)
+	}
+
+	wanted := idn.Normalize(name)
 	for _, domain := range domains {
-		if domain.Domain == name {
-			diags = append(diags, setDomainData(&domain, d)...)
-			id := strconv.Itoa(int(domain.Id))
-			d.SetId(id)
-			break
+		if idn.Normalize(domain.Domain) == wanted {
+			return &domain, nil
 		}
 	}
 
-	return diags
+	detail := fmt.Sprintf("No domain named %q on the account.", name)
+	if suggestions := similarDomains(wanted, domains); len(suggestions) > 0 {
+		detail += fmt.Sprintf(" Did you mean %s?", strings.Join(suggestions, ", "))
+	}
+
+	return nil, diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  "Domain not found",
+		Detail:   detail,
+	}}
 }
 
-func setDomainData(domain *domeneshop.Domain, d *schema.ResourceData) diag.Diagnostics {
-	var errs []error
+// similarDomains returns the quoted names of up to three domains within a
+// small edit distance of the normalized name, closest first.
+func similarDomains(name string, domains []model.Domain) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
 
-	errs = append(errs, d.Set("domain", domain.Domain))
-	errs = append(errs, d.Set("expiry_date", domain.ExpiryDate))
-	errs = append(errs, d.Set("id", domain.Id))
-	errs = append(errs, d.Set("nameservers", domain.Nameservers))
-	errs = append(errs, d.Set("registrant", domain.Registrant))
-	errs = append(errs, d.Set("renew", domain.Renew))
-	errs = append(errs, d.Set("services_dns", domain.Services.Dns))
-	errs = append(errs, d.Set("services_email", domain.Services.Email))
-	errs = append(errs, d.Set("services_registrar", domain.Services.Registrar))
-	errs = append(errs, d.Set("services_webhotell", domain.Services.Webhotel))
-	errs = append(errs, d.Set("status", domain.Status))
-	errs = append(errs, d.Set("registered_date", domain.RegisteredDate))
+	maxDistance := len(name) / 4
+	if maxDistance < 2 {
+		maxDistance = 2
+	}
 
-	var diags diag.Diagnostics
-	for _, err := range errs {
-		if err != nil {
-			diags = append(diags, diag.FromErr(err)...)
+	var candidates []candidate
+	for _, domain := range domains {
+		distance := levenshtein(name, idn.Normalize(domain.Domain))
+		if distance <= maxDistance {
+			candidates = append(candidates, candidate{domain.Domain, distance})
 		}
 	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].distance < candidates[j].distance
+	})
 
-	return diags
+	var names []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		names = append(names, strconv.Quote(candidates[i].name))
+	}
+	return names
 }
 
-func getDomains(client *http.Client) ([]domeneshop.Domain, error) {
-	response, err := client.Get(api.Domains())
-	if err != nil {
-		return nil, fmt.Errorf("HTTP get domains: %w", err)
+// levenshtein returns the number of single byte insertions, deletions and
+// substitutions turning a into b.
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
 	}
-	defer closeBody(response.Body)
 
-	var domains []domeneshop.Domain
-	err = json.NewDecoder(response.Body).Decode(&domains)
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
 
-	if err != nil {
-		return nil, fmt.Errorf("decoding domains: %w", err)
+	return previous[len(b)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+func flattenDomain(domain *model.Domain) map[string]interface{} {
+	return map[string]interface{}{
+		"domain":             domain.Domain,
+		"expiry_date":        domain.ExpiryDate,
+		"id":                 domain.Id,
+		"nameservers":        domain.Nameservers,
+		"registrant":         domain.Registrant,
+		"renew":              domain.Renew,
+		"services_dns":       domain.Services.Dns,
+		"services_email":     domain.Services.Email,
+		"services_registrar": domain.Services.Registrar,
+		"services_webhotell": domain.Services.Webhotel,
+		"status":             domain.Status,
+		"registered_date":    domain.RegisteredDate,
 	}
+}
 
-	return domains, nil
+func setDomainData(domain *model.Domain, d *schema.ResourceData) diag.Diagnostics {
+	var errs []error
+
+	for key, value := range flattenDomain(domain) {
+		errs = append(errs, d.Set(key, value))
+	}
+
+	var diags diag.Diagnostics
+	for _, err := range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
 }
@@ -0,0 +1,203 @@
+package domeneshop_test
+
+import (
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestAccDataSourceDomain_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddDomain("example.org")
+	domain := server.AddDomain("example.com")
+
+	state, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_domain", map[string]interface{}{
+		"domain": "example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.ID != strconv.Itoa(domain.Id) {
+		t.Errorf("expected id %d, got %s", domain.Id, state.ID)
+	}
+	if state.Attributes["services_dns"] != "true" || state.Attributes["nameservers.#"] != "3" {
+		t.Errorf("unexpected attributes %v", state.Attributes)
+	}
+}
+
+func TestAccDataSourceDomain_lookup(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	idn := server.AddDomain("xn--blbr-roah.no")
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		id     int
+	}{
+		"id":           {map[string]interface{}{"id": domain.Id}, domain.Id},
+		"case and dot": {map[string]interface{}{"domain": "Example.COM."}, domain.Id},
+		"unicode":      {map[string]interface{}{"domain": "Blåbær.no"}, idn.Id},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_domain", tc.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if state.ID != strconv.Itoa(tc.id) || state.Attributes["id"] != strconv.Itoa(tc.id) {
+				t.Errorf("expected id %d, got %s", tc.id, state.ID)
+			}
+		})
+	}
+}
+
+func TestAccDataSourceDomain_notFound(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+	server.AddDomain("example.no")
+	server.AddDomain("other.org")
+
+	_, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_domain", map[string]interface{}{
+		"domain": "exmple.com",
+	})
+	if err == nil || !strings.HasSuffix(err.Error(), `Did you mean "example.com"?`) {
+		t.Errorf("expected a not found error with suggestions, got %v", err)
+	}
+
+	_, err = testAccDataSource(t, testAccProvider(t, server), "domeneshop_domain", map[string]interface{}{
+		"id": 1,
+	})
+	if err == nil || !strings.Contains(err.Error(), "No domain with id 1") {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
+
+func TestAccDataSourceDomains_filter(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddDomain("example.com")
+	server.AddDomain("example.org")
+	server.AddDomain("other.no")
+
+	state, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_domains", map[string]interface{}{
+		"domain":       "example",
+		"services_dns": true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["domains.#"] != "2" {
+		t.Fatalf("expected 2 domains, got %v", state.Attributes)
+	}
+	if state.Attributes["domains.0.domain"] != "example.com" || state.Attributes["domains.1.domain"] != "example.org" {
+		t.Errorf("unexpected domains %v", state.Attributes)
+	}
+}
+
+func TestAccDataSourceDNSRecords_filter(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "MX", Data: "mx1.example.com", Priority: "10", Ttl: 3600})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "TXT", Data: "v=spf1 -all", Ttl: 3600})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "A", Data: "192.0.2.1", Ttl: 3600})
+
+	state, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_dns_records", map[string]interface{}{
+		"domain_id": domain.Id,
+		"host":      "@",
+		"type":      "MX",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.Attributes["records.#"] != "1" || state.Attributes["records.0.data"] != "mx1.example.com" {
+		t.Errorf("unexpected records %v", state.Attributes)
+	}
+}
+
+func TestAccDataSourceDNSRecords_fields(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	zero, one, three, critical, issue := 0, 1, 3, 128, 0
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "CAA", Data: "letsencrypt.org", Flags: &critical, Tag: &issue, Ttl: 3600})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "_443._tcp", Type: "TLSA", Data: "ABCDEF", Usage: &three, Selector: &one, Dtype: &zero, Ttl: 3600})
+
+	state, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_dns_records", map[string]interface{}{
+		"domain_id": domain.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"records.#":          "2",
+		"records.0.type":     "CAA",
+		"records.0.value":    "letsencrypt.org",
+		"records.0.flags":    "128",
+		"records.0.tag":      "issue",
+		"records.1.type":     "TLSA",
+		"records.1.data":     "ABCDEF",
+		"records.1.usage":    "3",
+		"records.1.selector": "1",
+		"records.1.dtype":    "0",
+	}
+	for key, value := range expected {
+		if actual := state.Attributes[key]; actual != value {
+			t.Errorf("attribute %s: expected %q, got %q", key, value, actual)
+		}
+	}
+}
+
+func TestAccDataSourceInvoices_status(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	server.AddInvoice(model.Invoice{Id: 1, Type: "invoice", Amount: 120, Currency: "NOK", Status: "paid"})
+	server.AddInvoice(model.Invoice{Id: 2, Type: "invoice", Amount: 240, Currency: "NOK", Status: "unpaid", DueDate: "2020-01-01"})
+
+	provider := testAccProvider(t, server)
+
+	state, err := testAccDataSource(t, provider, "domeneshop_invoices", map[string]interface{}{
+		"status": "unpaid",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Attributes["invoices.#"] != "1" || state.Attributes["invoices.0.id"] != "2" {
+		t.Errorf("unexpected invoices %v", state.Attributes)
+	}
+
+	state, err = testAccDataSource(t, provider, "domeneshop_invoice", map[string]interface{}{
+		"id": 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Attributes["amount"] != "240" || state.Attributes["due_date"] != "2020-01-01" {
+		t.Errorf("unexpected invoice %v", state.Attributes)
+	}
+}
@@ -0,0 +1,125 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"terraform-provider-domeneshop/domeneshop/client"
+)
+
+func dataSourceDomains() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceDomainsRead,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"services_dns": {
+				Type:     schema.TypeBool,
+				Optional: true,
+			},
+			"domains": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"domain": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"expiry_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"nameservers": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+						},
+						"registrant": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"renew": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"services_dns": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"services_email": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"services_registrar": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"services_webhotell": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"registered_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceDomainsRead(ctx context.Context, d *schema.ResourceData, i interface{}) diag.Diagnostics {
+	client := i.(client.API)
+
+	filter := d.Get("domain").(string)
+	status := d.Get("status").(string)
+	servicesDns, filterDns := d.GetOkExists("services_dns")
+
+	domains, err := client.ListDomains(ctx, filter)
+	if err != nil {
+		return apiError("reading domains", err)
+	}
+
+	flattened := make([]map[string]interface{}, 0, len(domains))
+	for _, domain := range domains {
+		if status != "" && domain.Status != status {
+			continue
+		}
+		if filterDns && domain.Services.Dns != servicesDns.(bool) {
+			continue
+		}
+		flattened = append(flattened, flattenDomain(&domain))
+	}
+
+	if err := d.Set("domains", flattened); err != nil {
+		return diag.FromErr(err)
+	}
+
+	dnsFilter := ""
+	if filterDns {
+		dnsFilter = strconv.FormatBool(servicesDns.(bool))
+	}
+	d.SetId(fmt.Sprintf("%s/%s/%s", filter, status, dnsFilter))
+
+	return nil
+}
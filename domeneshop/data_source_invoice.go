@@ -0,0 +1,90 @@
+package domeneshop
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+func dataSourceInvoice() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceInvoiceRead,
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"amount": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"currency": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"due_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"issued_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"paid_date": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceInvoiceRead(ctx context.Context, d *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := i.(client.API)
+
+	invoiceId := d.Get("id").(int)
+
+	invoice, err := client.GetInvoice(ctx, invoiceId)
+	if err != nil {
+		return apiError("reading invoice", err)
+	}
+
+	for key, value := range flattenInvoice(invoice) {
+		if err := d.Set(key, value); err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	d.SetId(strconv.Itoa(invoice.Id))
+
+	return diags
+}
+
+func flattenInvoice(invoice *model.Invoice) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          invoice.Id,
+		"type":        invoice.Type,
+		"amount":      invoice.Amount,
+		"currency":    invoice.Currency,
+		"due_date":    invoice.DueDate,
+		"issued_date": invoice.IssuedDate,
+		"paid_date":   invoice.PaidDate,
+		"status":      invoice.Status,
+		"url":         invoice.Url,
+	}
+}
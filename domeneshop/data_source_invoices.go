@@ -0,0 +1,89 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"terraform-provider-domeneshop/domeneshop/client"
+)
+
+func dataSourceInvoices() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceInvoicesRead,
+		Schema: map[string]*schema.Schema{
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"invoices": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"amount": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"currency": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"due_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"issued_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"paid_date": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceInvoicesRead(ctx context.Context, d *schema.ResourceData, i interface{}) diag.Diagnostics {
+	client := i.(client.API)
+
+	status := d.Get("status").(string)
+
+	invoices, err := client.ListInvoices(ctx, status)
+	if err != nil {
+		return apiError("reading invoices", err)
+	}
+
+	flattened := make([]map[string]interface{}, 0, len(invoices))
+	for _, invoice := range invoices {
+		flattened = append(flattened, flattenInvoice(&invoice))
+	}
+
+	if err := d.Set("invoices", flattened); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("invoices/%s", status))
+
+	return nil
+}
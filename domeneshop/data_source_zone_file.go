@@ -0,0 +1,52 @@
+package domeneshop
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/zonefile"
+)
+
+func dataSourceZoneFile() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceZoneFileRead,
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceZoneFileRead(ctx context.Context, d *schema.ResourceData, i interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+	client := i.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+
+	domain, err := client.GetDomain(ctx, domainId)
+	if err != nil {
+		return apiError("reading domain", err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return apiError("reading DNS records", err)
+	}
+
+	err = d.Set("content", zonefile.Render(domain.Domain, joinTXTData(records)))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(domainId))
+
+	return diags
+}
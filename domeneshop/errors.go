@@ -0,0 +1,52 @@
+package domeneshop
+
+import (
+	"errors"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"terraform-provider-domeneshop/domeneshop/client"
+)
+
+// readError handles an error from reading a resource. When the API reports
+// the resource as gone it is removed from state, so the next plan recreates
+// it. Any other error becomes a diagnostic.
+func readError(d *schema.ResourceData, resource string, err error) diag.Diagnostics {
+	if client.IsNotFound(err) && !d.IsNewResource() {
+		log.Printf("[WARN] %s %s not found, removing from state", resource, d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	return apiError(fmt.Sprintf("reading %s", resource), err)
+}
+
+// deleteError handles an error from deleting a resource, where a resource
+// that is already gone counts as deleted.
+func deleteError(resource string, err error) diag.Diagnostics {
+	if client.IsNotFound(err) {
+		return nil
+	}
+
+	return apiError(fmt.Sprintf("deleting %s", resource), err)
+}
+
+// apiError turns err into a diagnostic, with the status code and response
+// body in the detail when the API returned an error response.
+func apiError(summary string, err error) diag.Diagnostics {
+	var apiErr *client.Error
+	if errors.As(err, &apiErr) {
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Error %s: unexpected status code %d", summary, apiErr.StatusCode),
+			Detail:   fmt.Sprintf("%s %s returned %d. Response : %v", apiErr.Method, apiErr.URL, apiErr.StatusCode, apiErr.Body),
+		}}
+	}
+
+	return diag.Diagnostics{{
+		Severity: diag.Error,
+		Summary:  fmt.Sprintf("Error %s", summary),
+		Detail:   err.Error(),
+	}}
+}
@@ -0,0 +1,492 @@
+// Package fake implements an in-memory fake of the Domeneshop v0 API, for
+// running the provider without network access.
+package fake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// Server is a running fake API. Its URL is the endpoint to configure the
+// provider with.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextId   int
+	domains  map[int]*model.Domain
+	records  map[int]map[int]*model.DnsRecord
+	forwards map[int]map[string]*model.HttpForward
+	invoices map[int]*model.Invoice
+}
+
+// NewServer starts a fake API with no domains.
+func NewServer() *Server {
+	s := &Server{
+		nextId:   1000,
+		domains:  map[int]*model.Domain{},
+		records:  map[int]map[int]*model.DnsRecord{},
+		forwards: map[int]map[string]*model.HttpForward{},
+		invoices: map[int]*model.Invoice{},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// AddDomain registers an active domain with the DNS service enabled.
+func (s *Server) AddDomain(name string) model.Domain {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	domain := &model.Domain{
+		Id:             s.id(),
+		Domain:         name,
+		ExpiryDate:     "2030-01-01",
+		RegisteredDate: "2020-01-01",
+		Renew:          true,
+		Registrant:     "Ola Nordmann",
+		Status:         "active",
+		Nameservers:    []string{"ns1.hyp.net", "ns2.hyp.net", "ns3.hyp.net"},
+		Services: model.DomainServices{
+			Registrar: true,
+			Dns:       true,
+			Email:     true,
+			Webhotel:  "none",
+		},
+	}
+	s.domains[domain.Id] = domain
+	s.records[domain.Id] = map[int]*model.DnsRecord{}
+	s.forwards[domain.Id] = map[string]*model.HttpForward{}
+
+	return *domain
+}
+
+// AddInvoice registers an invoice, keeping its id.
+func (s *Server) AddInvoice(invoice model.Invoice) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.invoices[invoice.Id] = &invoice
+}
+
+// AddRecord inserts a record directly, as if created in the control panel,
+// and returns its id.
+func (s *Server) AddRecord(domainId int, record model.DnsRecord) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Id = s.id()
+	s.records[domainId][record.Id] = &record
+	return record.Id
+}
+
+// RemoveRecord deletes a record directly, as if deleted in the control panel.
+func (s *Server) RemoveRecord(domainId, recordId int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records[domainId], recordId)
+}
+
+// RemoveDomain deletes a domain and everything in it directly, as if it had
+// left the account.
+func (s *Server) RemoveDomain(domainId int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.domains, domainId)
+	delete(s.records, domainId)
+	delete(s.forwards, domainId)
+}
+
+// Records returns the records of a domain, ordered by id.
+func (s *Server) Records(domainId int) []model.DnsRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.listRecords(domainId, "", "")
+}
+
+// Forwards returns the forwards of a domain, ordered by host.
+func (s *Server) Forwards(domainId int) []model.HttpForward {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.listForwards(domainId)
+}
+
+func (s *Server) id() int {
+	s.nextId++
+	return s.nextId
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, _, ok := r.BasicAuth(); !ok {
+		writeError(w, http.StatusUnauthorized, "auth:missing", "HTTP Basic Auth is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.EscapedPath(), "/"), "/")
+	for i := range parts {
+		parts[i], _ = url.PathUnescape(parts[i])
+	}
+
+	switch {
+	case match(parts, "domains"):
+		s.handleDomains(w, r)
+	case match(parts, "domains", "*"):
+		s.withDomain(w, parts[1], func(domain *model.Domain) {
+			s.handleDomain(w, r, domain)
+		})
+	case match(parts, "domains", "*", "dns"):
+		s.withDomain(w, parts[1], func(domain *model.Domain) {
+			s.handleRecords(w, r, domain)
+		})
+	case match(parts, "domains", "*", "dns", "*"):
+		s.withDomain(w, parts[1], func(domain *model.Domain) {
+			s.handleRecord(w, r, domain, parts[3])
+		})
+	case match(parts, "domains", "*", "forwards"):
+		s.withDomain(w, parts[1], func(domain *model.Domain) {
+			s.handleForwards(w, r, domain)
+		})
+	case match(parts, "domains", "*", "forwards", "*"):
+		s.withDomain(w, parts[1], func(domain *model.Domain) {
+			s.handleForward(w, r, domain, parts[3])
+		})
+	case match(parts, "invoices"):
+		s.handleInvoices(w, r)
+	case match(parts, "invoices", "*"):
+		s.handleInvoice(w, r, parts[1])
+	case match(parts, "dyndns", "update"):
+		s.handleDynDNS(w, r)
+	default:
+		writeError(w, http.StatusNotFound, "path:not_found", "no such endpoint")
+	}
+}
+
+func (s *Server) handleDomains(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	filter := r.URL.Query().Get("domain")
+
+	domains := []model.Domain{}
+	for _, domain := range s.domains {
+		if strings.Contains(domain.Domain, filter) {
+			domains = append(domains, *domain)
+		}
+	}
+	sort.Slice(domains, func(i, j int) bool { return domains[i].Id < domains[j].Id })
+
+	writeJSON(w, http.StatusOK, domains)
+}
+
+func (s *Server) handleDomain(w http.ResponseWriter, r *http.Request, domain *model.Domain) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, domain)
+}
+
+func (s *Server) handleRecords(w http.ResponseWriter, r *http.Request, domain *model.Domain) {
+	switch r.Method {
+	case "GET":
+		query := r.URL.Query()
+		writeJSON(w, http.StatusOK, s.listRecords(domain.Id, query.Get("host"), query.Get("type")))
+	case "POST":
+		var record model.DnsRecord
+		if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+			writeError(w, http.StatusBadRequest, "record:invalid_json", err.Error())
+			return
+		}
+		if code, help := validateRecord(&record); code != "" {
+			writeError(w, http.StatusBadRequest, code, help)
+			return
+		}
+		record.Id = s.id()
+		s.records[domain.Id][record.Id] = &record
+
+		w.Header().Set("Location", fmt.Sprintf("/domains/%d/dns/%d", domain.Id, record.Id))
+		writeJSON(w, http.StatusCreated, model.InlineResponse201{Id: record.Id})
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleRecord(w http.ResponseWriter, r *http.Request, domain *model.Domain, rawId string) {
+	recordId, err := strconv.Atoi(rawId)
+	record, ok := s.records[domain.Id][recordId]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "record:not_found", "DNS record not found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, record)
+	case "PUT":
+		var updated model.DnsRecord
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			writeError(w, http.StatusBadRequest, "record:invalid_json", err.Error())
+			return
+		}
+		if code, help := validateRecord(&updated); code != "" {
+			writeError(w, http.StatusBadRequest, code, help)
+			return
+		}
+		updated.Id = record.Id
+		s.records[domain.Id][record.Id] = &updated
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		delete(s.records[domain.Id], record.Id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleForwards(w http.ResponseWriter, r *http.Request, domain *model.Domain) {
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, s.listForwards(domain.Id))
+	case "POST":
+		var forward model.HttpForward
+		if err := json.NewDecoder(r.Body).Decode(&forward); err != nil {
+			writeError(w, http.StatusBadRequest, "forward:invalid_json", err.Error())
+			return
+		}
+		if code, help := validateForward(&forward); code != "" {
+			writeError(w, http.StatusBadRequest, code, help)
+			return
+		}
+		if _, ok := s.forwards[domain.Id][forward.Host]; ok {
+			writeError(w, http.StatusConflict, "forward:collision", "a forward already exists for this host")
+			return
+		}
+		for _, record := range s.records[domain.Id] {
+			switch record.Type {
+			case "A", "AAAA", "ANAME", "CNAME":
+				if record.Host == forward.Host {
+					writeError(w, http.StatusConflict, "forward:collision", "a DNS record already exists for this host")
+					return
+				}
+			}
+		}
+		s.forwards[domain.Id][forward.Host] = &forward
+
+		w.Header().Set("Location", fmt.Sprintf("/domains/%d/forwards/%s", domain.Id, url.PathEscape(forward.Host)))
+		w.WriteHeader(http.StatusCreated)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleForward(w http.ResponseWriter, r *http.Request, domain *model.Domain, host string) {
+	forward, ok := s.forwards[domain.Id][host]
+	if !ok {
+		writeError(w, http.StatusNotFound, "forward:not_found", "forward not found")
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeJSON(w, http.StatusOK, forward)
+	case "PUT":
+		var updated model.HttpForward
+		if err := json.NewDecoder(r.Body).Decode(&updated); err != nil {
+			writeError(w, http.StatusBadRequest, "forward:invalid_json", err.Error())
+			return
+		}
+		if updated.Host != forward.Host {
+			writeError(w, http.StatusBadRequest, "forward:host_changed", "the host of a forward cannot be changed")
+			return
+		}
+		if code, help := validateForward(&updated); code != "" {
+			writeError(w, http.StatusBadRequest, code, help)
+			return
+		}
+		s.forwards[domain.Id][host] = &updated
+		writeJSON(w, http.StatusOK, updated)
+	case "DELETE":
+		delete(s.forwards[domain.Id], host)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleInvoices(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", "unpaid", "paid", "settled":
+	default:
+		writeError(w, http.StatusBadRequest, "invoice:invalid_status", "status must be one of unpaid, paid or settled")
+		return
+	}
+
+	invoices := []model.Invoice{}
+	for _, invoice := range s.invoices {
+		if status == "" || invoice.Status == status {
+			invoices = append(invoices, *invoice)
+		}
+	}
+	sort.Slice(invoices, func(i, j int) bool { return invoices[i].Id < invoices[j].Id })
+
+	writeJSON(w, http.StatusOK, invoices)
+}
+
+func (s *Server) handleInvoice(w http.ResponseWriter, r *http.Request, rawId string) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	invoiceId, err := strconv.Atoi(rawId)
+	invoice, ok := s.invoices[invoiceId]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "invoice:not_found", "invoice not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, invoice)
+}
+
+func (s *Server) handleDynDNS(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "GET" {
+		methodNotAllowed(w)
+		return
+	}
+
+	query := r.URL.Query()
+	hostname := strings.ToLower(query.Get("hostname"))
+	if hostname == "" {
+		writeError(w, http.StatusBadRequest, "ddns:missing_hostname", "hostname is required")
+		return
+	}
+
+	myip := query.Get("myip")
+	if myip == "" {
+		myip, _, _ = net.SplitHostPort(r.RemoteAddr)
+	}
+	ip := net.ParseIP(myip)
+	if ip == nil {
+		writeError(w, http.StatusBadRequest, "ddns:invalid_ip", "myip must be an IPv4 or IPv6 address")
+		return
+	}
+	recordType := "A"
+	if ip.To4() == nil {
+		recordType = "AAAA"
+	}
+
+	var domain *model.Domain
+	for _, candidate := range s.domains {
+		if hostname == candidate.Domain || strings.HasSuffix(hostname, "."+candidate.Domain) {
+			if domain == nil || len(candidate.Domain) > len(domain.Domain) {
+				domain = candidate
+			}
+		}
+	}
+	if domain == nil {
+		writeError(w, http.StatusNotFound, "ddns:domain_not_found", "no domain on the account matches hostname")
+		return
+	}
+
+	host := strings.TrimSuffix(strings.TrimSuffix(hostname, domain.Domain), ".")
+	if host == "" {
+		host = "@"
+	}
+
+	for _, record := range s.records[domain.Id] {
+		if record.Host == host && record.Type == recordType {
+			record.Data = ip.String()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	record := &model.DnsRecord{Id: s.id(), Host: host, Ttl: 3600, Type: recordType, Data: ip.String()}
+	s.records[domain.Id][record.Id] = record
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) withDomain(w http.ResponseWriter, rawId string, f func(domain *model.Domain)) {
+	domainId, err := strconv.Atoi(rawId)
+	domain, ok := s.domains[domainId]
+	if err != nil || !ok {
+		writeError(w, http.StatusNotFound, "domain:not_found", "domain not found")
+		return
+	}
+	f(domain)
+}
+
+func (s *Server) listRecords(domainId int, host, recordType string) []model.DnsRecord {
+	records := []model.DnsRecord{}
+	for _, record := range s.records[domainId] {
+		if host != "" && record.Host != host {
+			continue
+		}
+		if recordType != "" && record.Type != recordType {
+			continue
+		}
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Id < records[j].Id })
+	return records
+}
+
+func (s *Server) listForwards(domainId int) []model.HttpForward {
+	forwards := []model.HttpForward{}
+	for _, forward := range s.forwards[domainId] {
+		forwards = append(forwards, *forward)
+	}
+	sort.Slice(forwards, func(i, j int) bool { return forwards[i].Host < forwards[j].Host })
+	return forwards
+}
+
+func match(parts []string, pattern ...string) bool {
+	if len(parts) != len(pattern) {
+		return false
+	}
+	for i := range pattern {
+		if pattern[i] != "*" && pattern[i] != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, code, help string) {
+	writeJSON(w, status, map[string]string{"code": code, "help": help})
+}
+
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "method:not_allowed", "method not allowed")
+}
@@ -0,0 +1,103 @@
+package fake
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// validateRecord applies the rules the real API enforces on DNS records,
+// filling in the default TTL. It returns an error code and help text when
+// the record is rejected.
+func validateRecord(record *model.DnsRecord) (string, string) {
+	if record.Host == "" {
+		return "record:invalid_host", "host is required, use @ for the domain itself"
+	}
+
+	if record.Ttl == 0 {
+		record.Ttl = 3600
+	}
+	if record.Ttl%60 != 0 {
+		return "record:invalid_ttl", "TTL must be a multiple of 60"
+	}
+	if record.Ttl < 60 || record.Ttl > 604800 {
+		return "record:invalid_ttl", "TTL must be between 60 and 604800"
+	}
+
+	if record.Data == "" {
+		return "record:invalid_data", "data is required"
+	}
+
+	switch record.Type {
+	case "A":
+		if ip := net.ParseIP(record.Data); ip == nil || ip.To4() == nil {
+			return "record:invalid_data", "data must be an IPv4 address"
+		}
+	case "AAAA":
+		if ip := net.ParseIP(record.Data); ip == nil || ip.To4() != nil {
+			return "record:invalid_data", "data must be an IPv6 address"
+		}
+	case "ANAME", "CNAME", "NS":
+	case "TXT":
+		if len(record.Data) > 255 && !strings.HasPrefix(record.Data, `"`) {
+			return "record:invalid_data", "TXT strings can be at most 255 bytes, split longer data into several quoted strings"
+		}
+	case "MX":
+		if _, err := strconv.Atoi(record.Priority); err != nil {
+			return "record:invalid_priority", "priority is required for MX records"
+		}
+	case "SRV":
+		if _, err := strconv.Atoi(record.Priority); err != nil {
+			return "record:invalid_priority", "priority is required for SRV records"
+		}
+		if record.Port < 1 || record.Port > 65535 {
+			return "record:invalid_port", "port must be between 1 and 65535"
+		}
+	case "CAA":
+		if record.Flags == nil || *record.Flags < 0 || *record.Flags > 255 {
+			return "record:invalid_flags", "flags must be between 0 and 255"
+		}
+		if record.Tag == nil || *record.Tag < 0 || *record.Tag > 2 {
+			return "record:invalid_tag", "tag must be 0 (issue), 1 (issuewild) or 2 (iodef)"
+		}
+	case "DS":
+		if record.Tag == nil || *record.Tag < 0 || *record.Tag > 65535 {
+			return "record:invalid_tag", "tag must be between 0 and 65535"
+		}
+		if record.Alg == nil || *record.Alg < 0 || *record.Alg > 255 {
+			return "record:invalid_alg", "alg must be between 0 and 255"
+		}
+		if record.Digest == nil || *record.Digest < 0 || *record.Digest > 255 {
+			return "record:invalid_digest", "digest must be between 0 and 255"
+		}
+	case "TLSA":
+		if record.Usage == nil || *record.Usage < 0 || *record.Usage > 3 {
+			return "record:invalid_usage", "usage must be between 0 and 3"
+		}
+		if record.Selector == nil || *record.Selector < 0 || *record.Selector > 1 {
+			return "record:invalid_selector", "selector must be 0 or 1"
+		}
+		if record.Dtype == nil || *record.Dtype < 0 || *record.Dtype > 2 {
+			return "record:invalid_dtype", "dtype must be between 0 and 2"
+		}
+	default:
+		return "record:invalid_type", "unsupported record type " + record.Type
+	}
+
+	return "", ""
+}
+
+func validateForward(forward *model.HttpForward) (string, string) {
+	if forward.Host == "" {
+		return "forward:invalid_host", "host is required, use @ for the domain itself"
+	}
+
+	parsed, err := url.Parse(forward.Url)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return "forward:invalid_url", "url must be absolute and include the scheme"
+	}
+
+	return "", ""
+}
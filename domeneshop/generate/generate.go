@@ -0,0 +1,245 @@
+// Package generate writes Terraform configuration, with import blocks, for
+// the domains, DNS records and HTTP forwards of an existing account.
+package generate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// Generator writes configuration for domains, keeping resource names unique
+// across all of them.
+type Generator struct {
+	api   client.API
+	names map[string]bool
+}
+
+// New returns a Generator reading from api.
+func New(api client.API) *Generator {
+	return &Generator{
+		api:   api,
+		names: map[string]bool{},
+	}
+}
+
+// Domain writes the configuration of domain to w: a domeneshop_domain data
+// source, and a resource with an import block for each DNS record and HTTP
+// forward.
+func (gen *Generator) Domain(ctx context.Context, domain model.Domain, w io.Writer) error {
+	records, err := gen.api.ListRecords(ctx, domain.Id, "", "")
+	if err != nil {
+		return err
+	}
+	forwards, err := gen.api.ListForwards(ctx, domain.Id)
+	if err != nil {
+		return err
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].Host != records[j].Host {
+			return records[i].Host < records[j].Host
+		}
+		if records[i].Type != records[j].Type {
+			return records[i].Type < records[j].Type
+		}
+		return records[i].Id < records[j].Id
+	})
+	sort.SliceStable(forwards, func(i, j int) bool {
+		return forwards[i].Host < forwards[j].Host
+	})
+
+	g := writer{w: w, names: gen.names}
+	domainName := g.name(domain.Domain)
+	domainId := fmt.Sprintf("data.domeneshop_domain.%s.id", domainName)
+
+	g.printf("# %s\n\n", domain.Domain)
+	g.block(fmt.Sprintf("data \"domeneshop_domain\" %q", domainName), []attribute{
+		{"domain", quote(domain.Domain)},
+	})
+
+	for _, record := range records {
+		attributes, ok := recordAttributes(record)
+		if !ok {
+			g.printf("# Skipped %s record %d for %s: not supported by domeneshop_dns_record\n\n", record.Type, record.Id, record.Host)
+			continue
+		}
+
+		name := g.name(domain.Domain + "_" + record.Host + "_" + record.Type)
+		g.importBlock("domeneshop_dns_record."+name, fmt.Sprintf("%d/%d", domain.Id, record.Id))
+		g.block(fmt.Sprintf("resource \"domeneshop_dns_record\" %q", name), append([]attribute{
+			{"domain_id", domainId},
+			{"host", quote(record.Host)},
+			{"type", quote(record.Type)},
+			{"ttl", strconv.Itoa(record.Ttl)},
+		}, attributes...))
+	}
+
+	for _, forward := range forwards {
+		name := g.name(domain.Domain + "_" + forward.Host + "_forward")
+		attributes := []attribute{
+			{"domain_id", domainId},
+			{"host", quote(forward.Host)},
+			{"url", quote(forward.Url)},
+		}
+		if forward.Frame {
+			attributes = append(attributes, attribute{"frame", "true"})
+		}
+
+		g.importBlock("domeneshop_http_forward."+name, fmt.Sprintf("%d/%s", domain.Id, forward.Host))
+		g.block(fmt.Sprintf("resource \"domeneshop_http_forward\" %q", name), attributes)
+	}
+
+	return g.err
+}
+
+// recordAttributes returns the type specific attributes of record, or false
+// if domeneshop_dns_record doesn't support its type.
+func recordAttributes(record model.DnsRecord) ([]attribute, bool) {
+	switch record.Type {
+	case "A", "AAAA", "ANAME", "CNAME", "NS", "TXT":
+		return []attribute{{"data", quote(record.Data)}}, true
+	case "MX":
+		return []attribute{
+			{"data", quote(record.Data)},
+			{"priority", quote(record.Priority)},
+		}, true
+	case "SRV":
+		return []attribute{
+			{"data", quote(record.Data)},
+			{"priority", quote(record.Priority)},
+			{"weight", strconv.Itoa(record.Weight)},
+			{"port", strconv.Itoa(record.Port)},
+		}, true
+	case "CAA":
+		tag := model.IntValue(record.Tag)
+		if tag < 0 || tag >= len(model.CAATags) {
+			return nil, false
+		}
+		return []attribute{
+			{"flags", strconv.Itoa(model.IntValue(record.Flags))},
+			{"tag", quote(model.CAATags[tag])},
+			{"value", quote(record.Data)},
+		}, true
+	case "DS":
+		return []attribute{
+			{"key_tag", strconv.Itoa(model.IntValue(record.Tag))},
+			{"alg", strconv.Itoa(model.IntValue(record.Alg))},
+			{"digest_type", strconv.Itoa(model.IntValue(record.Digest))},
+			{"digest", quote(record.Data)},
+		}, true
+	case "TLSA":
+		return []attribute{
+			{"usage", strconv.Itoa(model.IntValue(record.Usage))},
+			{"selector", strconv.Itoa(model.IntValue(record.Selector))},
+			{"dtype", strconv.Itoa(model.IntValue(record.Dtype))},
+			{"data", quote(record.Data)},
+		}, true
+	default:
+		return nil, false
+	}
+}
+
+type attribute struct {
+	key   string
+	value string
+}
+
+type writer struct {
+	w     io.Writer
+	names map[string]bool
+	err   error
+}
+
+func (g *writer) printf(format string, args ...interface{}) {
+	if g.err != nil {
+		return
+	}
+	_, g.err = fmt.Fprintf(g.w, format, args...)
+}
+
+// block writes a block with its attributes aligned like terraform fmt does.
+func (g *writer) block(header string, attributes []attribute) {
+	width := 0
+	for _, a := range attributes {
+		if len(a.key) > width {
+			width = len(a.key)
+		}
+	}
+
+	g.printf("%s {\n", header)
+	for _, a := range attributes {
+		g.printf("  %-*s = %s\n", width, a.key, a.value)
+	}
+	g.printf("}\n\n")
+}
+
+func (g *writer) importBlock(to, id string) {
+	g.block("import", []attribute{
+		{"to", to},
+		{"id", quote(id)},
+	})
+}
+
+// name turns s into a unique resource name.
+func (g *writer) name(s string) string {
+	var name strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			name.WriteRune(r)
+		case r == '@':
+			name.WriteString("apex")
+		case r == '*':
+			name.WriteString("wildcard")
+		default:
+			name.WriteRune('_')
+		}
+	}
+
+	base := strings.Trim(name.String(), "_-")
+	if base == "" || base[0] >= '0' && base[0] <= '9' {
+		base = "r_" + base
+	}
+
+	unique := base
+	for i := 2; g.names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", base, i)
+	}
+	g.names[unique] = true
+
+	return unique
+}
+
+// quote returns s as an HCL string, escaping template sequences.
+func quote(s string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteRune(r)
+		case r == '\n':
+			quoted.WriteString(`\n`)
+		case r == '\r':
+			quoted.WriteString(`\r`)
+		case r == '\t':
+			quoted.WriteString(`\t`)
+		case r < ' ' || r == 0x7f:
+			fmt.Fprintf(&quoted, `\u%04x`, r)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			quoted.WriteRune(r)
+			quoted.WriteRune(r)
+		default:
+			quoted.WriteRune(r)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
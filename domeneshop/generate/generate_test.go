@@ -0,0 +1,75 @@
+package generate_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/generate"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestDomain(t *testing.T) {
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	www := server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "A", Data: "192.0.2.1", Ttl: 300})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "A", Data: "192.0.2.2", Ttl: 300})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "MX", Data: "mx.example.com", Priority: "10", Ttl: 3600})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "TXT", Data: `v=spf1 "${quoted}" -all`, Ttl: 3600})
+
+	endpoint, err := api.NewEndpoint(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httpClient := &http.Client{Transport: authTransport{}}
+	generator := generate.New(client.New(httpClient, endpoint))
+
+	var out bytes.Buffer
+	if err := generator.Domain(context.Background(), domain, &out); err != nil {
+		t.Fatal(err)
+	}
+	hcl := out.String()
+
+	for _, expected := range []string{
+		"data \"domeneshop_domain\" \"example_com\" {\n  domain = \"example.com\"\n}\n",
+		"import {\n  to = domeneshop_dns_record.example_com_www_a\n  id = \"" + strconv.Itoa(domain.Id) + "/" + strconv.Itoa(www) + "\"\n}\n",
+		"resource \"domeneshop_dns_record\" \"example_com_www_a\" {\n" +
+			"  domain_id = data.domeneshop_domain.example_com.id\n" +
+			"  host      = \"www\"\n" +
+			"  type      = \"A\"\n" +
+			"  ttl       = 300\n" +
+			"  data      = \"192.0.2.1\"\n" +
+			"}\n",
+		"resource \"domeneshop_dns_record\" \"example_com_www_a_2\" {",
+		"resource \"domeneshop_dns_record\" \"example_com_apex_mx\" {",
+		"  priority  = \"10\"\n",
+		`  data      = "v=spf1 \"$${quoted}\" -all"` + "\n",
+	} {
+		if !strings.Contains(hcl, expected) {
+			t.Errorf("expected the configuration to contain:\n%s\ngot:\n%s", expected, hcl)
+		}
+	}
+
+	// Names stay unique across domains.
+	out.Reset()
+	if err := generator.Domain(context.Background(), domain, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "data \"domeneshop_domain\" \"example_com_2\"") {
+		t.Errorf("expected a new name for the second data source, got:\n%s", out.String())
+	}
+}
+
+// authTransport adds the credentials the fake server expects.
+type authTransport struct{}
+
+func (authTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	request.SetBasicAuth("token", "secret")
+	return http.DefaultTransport.RoundTrip(request)
+}
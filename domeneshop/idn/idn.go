@@ -0,0 +1,155 @@
+// Package idn converts internationalized domain names to their ASCII form,
+// so names can be compared whichever form they were written in.
+package idn
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ToASCII returns name in lower case, without a trailing dot, with each label
+// that isn't ASCII encoded as punycode (RFC 3492) with the "xn--" prefix.
+// Unlike full IDNA it doesn't normalize or validate the labels.
+func ToASCII(name string) (string, error) {
+	name = strings.TrimSuffix(strings.ToLower(name), ".")
+
+	labels := strings.Split(name, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		if !utf8.ValidString(label) {
+			return "", fmt.Errorf("%q is not valid UTF-8", label)
+		}
+
+		encoded, err := punycode(label)
+		if err != nil {
+			return "", fmt.Errorf("encoding %q: %w", label, err)
+		}
+		labels[i] = "xn--" + encoded
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+// Normalize returns name in the form domain names are compared in, as by
+// ToASCII. A name that can't be encoded is only lower cased and stripped of
+// the trailing dot.
+func Normalize(name string) string {
+	ascii, err := ToASCII(name)
+	if err != nil {
+		return strings.TrimSuffix(strings.ToLower(name), ".")
+	}
+	return ascii
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// Bootstring parameters for punycode, from RFC 3492 section 5.
+const (
+	base        = 36
+	tMin        = 1
+	tMax        = 26
+	skew        = 38
+	damp        = 700
+	initialBias = 72
+	initialN    = 128
+)
+
+// punycode encodes s as described in RFC 3492 section 6.3.
+func punycode(s string) (string, error) {
+	runes := []rune(s)
+
+	var out strings.Builder
+	for _, r := range runes {
+		if r < initialN {
+			out.WriteRune(r)
+		}
+	}
+	basic := out.Len()
+	handled := basic
+	if basic > 0 {
+		out.WriteByte('-')
+	}
+
+	n, delta, bias := rune(initialN), 0, initialBias
+	for handled < len(runes) {
+		m := rune(utf8.MaxRune)
+		for _, r := range runes {
+			if r >= n && r < m {
+				m = r
+			}
+		}
+
+		if int(m-n) > (1<<31-1-delta)/(handled+1) {
+			return "", fmt.Errorf("overflow")
+		}
+		delta += int(m-n) * (handled + 1)
+		n = m
+
+		for _, r := range runes {
+			if r < n {
+				delta++
+			}
+			if r != n {
+				continue
+			}
+
+			q := delta
+			for k := base; ; k += base {
+				t := k - bias
+				if t < tMin {
+					t = tMin
+				} else if t > tMax {
+					t = tMax
+				}
+				if q < t {
+					break
+				}
+				out.WriteByte(digit(t + (q-t)%(base-t)))
+				q = (q - t) / (base - t)
+			}
+			out.WriteByte(digit(q))
+
+			bias = adapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return out.String(), nil
+}
+
+func adapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= damp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((base-tMin)*tMax)/2 {
+		delta /= base - tMin
+		k += base
+	}
+	return k + (base-tMin+1)*delta/(delta+skew)
+}
+
+func digit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
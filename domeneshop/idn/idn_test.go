@@ -0,0 +1,44 @@
+package idn_test
+
+import (
+	"terraform-provider-domeneshop/domeneshop/idn"
+	"testing"
+)
+
+func TestToASCII(t *testing.T) {
+	cases := map[string]string{
+		"example.com":      "example.com",
+		"Example.COM.":     "example.com",
+		"bücher.de":        "xn--bcher-kva.de",
+		"blåbær.no":        "xn--blbr-roah.no",
+		"ærlig.no":         "xn--rlig-uoa.no",
+		"München.DE.":      "xn--mnchen-3ya.de",
+		"例え.テスト":           "xn--r8jz45g.xn--zckzah",
+		"日本語":              "xn--wgv71a119e",
+		"xn--bcher-kva.de": "xn--bcher-kva.de",
+	}
+
+	for name, expected := range cases {
+		ascii, err := idn.ToASCII(name)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if ascii != expected {
+			t.Errorf("%s: expected %s, got %s", name, expected, ascii)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := map[string]string{
+		"Example.COM.": "example.com",
+		"blåbær.no":    "xn--blbr-roah.no",
+	}
+
+	for name, expected := range cases {
+		if normalized := idn.Normalize(name); normalized != expected {
+			t.Errorf("%q: expected %q, got %q", name, expected, normalized)
+		}
+	}
+}
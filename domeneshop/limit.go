@@ -0,0 +1,125 @@
+package domeneshop
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// LimitTransport limits the rate of requests with a token bucket, and the
+// number of requests in flight. A request holds its slot until its response
+// body is closed. It is safe for concurrent use, and meant to be shared by
+// everything talking to the API.
+type LimitTransport struct {
+	t http.RoundTripper
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+
+	slots chan struct{}
+}
+
+// NewLimitTransport returns a LimitTransport sending requestsPerSecond
+// requests per second, after an initial burst, and at most maxConcurrent at
+// once. A zero requestsPerSecond or maxConcurrent disables that limit.
+func NewLimitTransport(t http.RoundTripper, requestsPerSecond float64, burst int, maxConcurrent int) *LimitTransport {
+	lt := &LimitTransport{
+		t:    t,
+		rate: requestsPerSecond,
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	lt.burst = float64(burst)
+	lt.tokens = lt.burst
+	if maxConcurrent > 0 {
+		lt.slots = make(chan struct{}, maxConcurrent)
+	}
+	return lt
+}
+
+func (lt *LimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	if lt.slots != nil {
+		select {
+		case lt.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	if err := lt.wait(ctx); err != nil {
+		lt.release()
+		return nil, err
+	}
+
+	resp, err := lt.t.RoundTrip(req)
+	if err != nil {
+		lt.release()
+		return nil, err
+	}
+
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: lt.release}
+	return resp, nil
+}
+
+// wait takes a token from the bucket, waiting for one to be added if it is
+// empty.
+func (lt *LimitTransport) wait(ctx context.Context) error {
+	if lt.rate <= 0 {
+		return nil
+	}
+
+	lt.mu.Lock()
+	now := time.Now()
+	if !lt.last.IsZero() {
+		lt.tokens += now.Sub(lt.last).Seconds() * lt.rate
+		if lt.tokens > lt.burst {
+			lt.tokens = lt.burst
+		}
+	}
+	lt.last = now
+
+	// Take the token now, even if it is not there yet, so waiting requests
+	// are served in order.
+	lt.tokens--
+	wait := time.Duration(-lt.tokens / lt.rate * float64(time.Second))
+	lt.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+
+	if err := sleep(ctx, wait); err != nil {
+		lt.mu.Lock()
+		lt.tokens++
+		lt.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (lt *LimitTransport) release() {
+	if lt.slots != nil {
+		<-lt.slots
+	}
+}
+
+// releaseBody releases the slot of a request once its body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,5 @@
+package model
+
+// CAATags are the tags of CAA records, in the order of the numbers the API
+// uses for them in Tag.
+var CAATags = []string{"issue", "issuewild", "iodef"}
@@ -0,0 +1,10 @@
+package model
+
+// IntValue returns the value of an optional integer field of a record, like
+// Flags or Tag, or 0 if it isn't set.
+func IntValue(value *int) int {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
@@ -27,4 +27,18 @@ type DnsRecord struct {
 	Weight int `json:"weight,omitempty"`
 	// SRV record port. The port where the service is found.
 	Port int `json:"port,omitempty"`
+	// CAA record flags, usually 0.
+	Flags *int `json:"flags,omitempty"`
+	// CAA record tag (0 = issue, 1 = issuewild, 2 = iodef), or DS record key tag.
+	Tag *int `json:"tag,omitempty"`
+	// DS record algorithm.
+	Alg *int `json:"alg,omitempty"`
+	// DS record digest type.
+	Digest *int `json:"digest,omitempty"`
+	// TLSA record certificate usage.
+	Usage *int `json:"usage,omitempty"`
+	// TLSA record selector.
+	Selector *int `json:"selector,omitempty"`
+	// TLSA record matching type.
+	Dtype *int `json:"dtype,omitempty"`
 }
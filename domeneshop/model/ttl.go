@@ -0,0 +1,21 @@
+package model
+
+import "fmt"
+
+// MinTTL and MaxTTL bound the TTL of a record in seconds. The API also wants
+// it to be a multiple of 60.
+const (
+	MinTTL = 60
+	MaxTTL = 604800
+)
+
+// CheckTTL returns an error if ttl isn't a TTL the API accepts.
+func CheckTTL(ttl int) error {
+	if ttl < MinTTL || ttl > MaxTTL {
+		return fmt.Errorf("must be between %d and %d seconds, got %d", MinTTL, MaxTTL, ttl)
+	}
+	if ttl%60 != 0 {
+		return fmt.Errorf("must be a multiple of 60 seconds, got %d", ttl)
+	}
+	return nil
+}
@@ -3,9 +3,12 @@ package domeneshop
 import (
 	"context"
 	"encoding/base64"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"net/http"
+	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/client"
 	"time"
 )
 
@@ -24,12 +27,54 @@ func Provider() *schema.Provider {
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("DOMENESHOP_SECRET", nil),
 			},
+			"endpoint": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("DOMENESHOP_ENDPOINT", api.DefaultURL),
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultHTTPClientConfig.MaxRetries,
+				ValidateFunc: validateIntBetween(0, 20),
+			},
+			"request_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      int(DefaultHTTPClientConfig.RequestTimeout / time.Second),
+				ValidateFunc: validateIntBetween(1, 600),
+			},
+			"requests_per_second": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultHTTPClientConfig.RequestsPerSecond,
+				ValidateFunc: validateIntBetween(0, 1000),
+			},
+			"max_concurrent_requests": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      DefaultHTTPClientConfig.MaxConcurrentRequests,
+				ValidateFunc: validateIntBetween(0, 100),
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"domeneshop_dns_record": resourceDNSRecord(),
+			"domeneshop_dmarc":          resourceDMARC(),
+			"domeneshop_dkim_key":       resourceDKIMKey(),
+			"domeneshop_dns_record":     resourceDNSRecord(),
+			"domeneshop_dns_record_set": resourceDNSRecordSet(),
+			"domeneshop_dns_zone":       resourceDNSZone(),
+			"domeneshop_dynamic_dns":    resourceDynamicDNS(),
+			"domeneshop_http_forward":   resourceHTTPForward(),
+			"domeneshop_spf_record":     resourceSPFRecord(),
+			"domeneshop_zone_file":      resourceZoneFile(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"domeneshop_domain": dataSourceDomain(),
+			"domeneshop_domain":      dataSourceDomain(),
+			"domeneshop_dns_records": dataSourceDNSRecords(),
+			"domeneshop_domains":     dataSourceDomains(),
+			"domeneshop_invoice":     dataSourceInvoice(),
+			"domeneshop_invoices":    dataSourceInvoices(),
+			"domeneshop_zone_file":   dataSourceZoneFile(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -57,21 +102,93 @@ func providerConfigure(_ context.Context, d *schema.ResourceData) (interface{},
 		})
 	}
 
+	endpoint, err := api.NewEndpoint(d.Get("endpoint").(string))
+	if err != nil {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Unable to create domeneshop client",
+			Detail:   fmt.Sprintf("Invalid `endpoint`: %v", err),
+		})
+	}
+
 	if len(diags) > 0 {
 		return nil, diags
 	}
 
-	client := &http.Client{
-		Timeout: 20 * time.Second,
-		Transport: &AddHeaderTransport{
-			T: http.DefaultTransport,
-			Headers: map[string]string{
-				"Authorization": basicAuth(token, secret),
-			},
-		},
+	config := HTTPClientConfig{
+		Token:                 token,
+		Secret:                secret,
+		MaxRetries:            d.Get("max_retries").(int),
+		RequestTimeout:        time.Duration(d.Get("request_timeout").(int)) * time.Second,
+		RequestsPerSecond:     d.Get("requests_per_second").(int),
+		MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+	}
+
+	return client.NewCache(client.New(NewHTTPClient(config), endpoint), recordCacheTTL, config.Timeout()), diags
+}
+
+// recordCacheTTL is how long the records of a domain are cached for reads of
+// single records. Writes through the provider drop them right away.
+const recordCacheTTL = 30 * time.Second
+
+// HTTPClientConfig configures the HTTP client talking to the API.
+type HTTPClientConfig struct {
+	Token  string
+	Secret string
+	// MaxRetries is the number of retries of a failed request.
+	MaxRetries int
+	// RequestTimeout limits each attempt of a request.
+	RequestTimeout time.Duration
+	// RequestsPerSecond and MaxConcurrentRequests limit the requests sent,
+	// counting retries, where zero means no limit.
+	RequestsPerSecond     int
+	MaxConcurrentRequests int
+}
+
+// DefaultHTTPClientConfig holds the defaults of the provider settings for the
+// HTTP client, for other users of NewHTTPClient to start from.
+var DefaultHTTPClientConfig = HTTPClientConfig{
+	MaxRetries:            4,
+	RequestTimeout:        20 * time.Second,
+	RequestsPerSecond:     5,
+	MaxConcurrentRequests: 4,
+}
+
+// maxBackoff is the longest wait between attempts of a request.
+const maxBackoff = 30 * time.Second
+
+// Timeout returns the longest a request may take, counting every attempt and
+// the waits between them, or 0 if there is no request timeout.
+func (config HTTPClientConfig) Timeout() time.Duration {
+	if config.RequestTimeout <= 0 {
+		return 0
 	}
+	return time.Duration(config.MaxRetries+1)*config.RequestTimeout + time.Duration(config.MaxRetries)*maxBackoff
+}
 
-	return client, diags
+// NewHTTPClient returns an HTTP client authenticating with the token and
+// secret of config, retrying failed requests and limiting the requests sent.
+// Everything using the client shares its limits.
+func NewHTTPClient(config HTTPClientConfig) *http.Client {
+	return &http.Client{
+		Transport: &RetryTransport{
+			T: NewLimitTransport(
+				&AddHeaderTransport{
+					T: http.DefaultTransport,
+					Headers: map[string]string{
+						"Authorization": basicAuth(config.Token, config.Secret),
+					},
+				},
+				float64(config.RequestsPerSecond),
+				config.RequestsPerSecond,
+				config.MaxConcurrentRequests,
+			),
+			MaxRetries: config.MaxRetries,
+			Timeout:    config.RequestTimeout,
+			MinBackoff: 500 * time.Millisecond,
+			MaxBackoff: maxBackoff,
+		},
+	}
 }
 
 type AddHeaderTransport struct {
@@ -0,0 +1,220 @@
+package domeneshop
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strings"
+)
+
+// dkimKeySuffix follows the selector in the host of a DKIM key.
+const dkimKeySuffix = "._domainkey"
+
+// minDKIMRSAKeyBits is the shortest RSA key verifiers accept, from RFC 8301
+// section 3.2.
+const minDKIMRSAKeyBits = 1024
+
+var dkimKeyTypes = []string{"rsa", "ed25519"}
+
+func resourceDKIMKey() *schema.Resource {
+	return dkimKeyKind().resource()
+}
+
+func dkimKeyKind() *txtRecordKind {
+	return &txtRecordKind{
+		name: "DKIM key",
+		tag:  "v=DKIM1",
+		arguments: map[string]*schema.Schema{
+			"selector": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				StateFunc:    normalizeDNSHostState,
+				ValidateFunc: validateDKIMSelector,
+			},
+			"key_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "rsa",
+				ValidateFunc: validateOneOf(dkimKeyTypes...),
+			},
+			"public_key": {
+				Type:         schema.TypeString,
+				Required:     true,
+				StateFunc:    normalizeDKIMPublicKeyState,
+				ValidateFunc: validateDKIMPublicKey,
+			},
+		},
+		host: func(get func(key string) interface{}) string {
+			return get("selector").(string) + dkimKeySuffix
+		},
+		render:   renderDKIMKey,
+		validate: validateDKIMKeyType,
+		flatten:  flattenDKIMKey,
+	}
+}
+
+// renderDKIMKey returns the DKIM key record of the arguments.
+func renderDKIMKey(get func(key string) interface{}) string {
+	return "v=DKIM1; k=" + get("key_type").(string) + "; p=" + normalizeDKIMPublicKey(get("public_key").(string))
+}
+
+// validateDKIMKeyType checks that the public key is of the key type.
+func validateDKIMKeyType(get func(key string) interface{}) error {
+	keyType := get("key_type").(string)
+
+	key, err := decodeDKIMPublicKey(get("public_key").(string))
+	if err != nil {
+		return fmt.Errorf("public_key: %w", err)
+	}
+
+	switch keyType {
+	case "rsa":
+		parsed, err := x509.ParsePKIXPublicKey(key)
+		rsaKey, ok := parsed.(*rsa.PublicKey)
+		if err != nil || !ok {
+			return fmt.Errorf("public_key: not an RSA public key, but key_type is %q", keyType)
+		}
+		if bits := rsaKey.N.BitLen(); bits < minDKIMRSAKeyBits {
+			return fmt.Errorf("public_key: RSA key of %d bits is shorter than the %d bits verifiers accept", bits, minDKIMRSAKeyBits)
+		}
+	case "ed25519":
+		if len(key) != ed25519.PublicKeySize {
+			return fmt.Errorf("public_key: not an Ed25519 public key, but key_type is %q", keyType)
+		}
+	}
+
+	return nil
+}
+
+// flattenDKIMKey returns the arguments of a DKIM key record, or false if it
+// isn't one or has tags they don't express.
+func flattenDKIMKey(host, data string) (map[string]interface{}, bool) {
+	selector := strings.TrimSuffix(host, dkimKeySuffix)
+	if selector == host {
+		return nil, false
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(data, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) < 2 || tags[0] != "v=DKIM1" {
+		return nil, false
+	}
+
+	arguments := map[string]interface{}{
+		"selector":   selector,
+		"key_type":   "rsa",
+		"public_key": "",
+	}
+	for _, tag := range tags[1:] {
+		i := strings.IndexByte(tag, '=')
+		if i < 0 {
+			return nil, false
+		}
+		key, value := strings.TrimSpace(tag[:i]), strings.TrimSpace(tag[i+1:])
+
+		switch key {
+		case "k":
+			if value = strings.ToLower(value); !stringInSlice(value, dkimKeyTypes) {
+				return nil, false
+			}
+			arguments["key_type"] = value
+		case "p":
+			arguments["public_key"] = strings.Join(strings.Fields(value), "")
+		default:
+			return nil, false
+		}
+	}
+
+	return arguments, arguments["public_key"] != ""
+}
+
+// decodeDKIMPublicKey returns the key as published in a DKIM record: the DER
+// encoded SubjectPublicKeyInfo of RSA keys, or the 32 bytes of Ed25519 keys
+// (RFC 8463). The key may be PEM encoded, or base64 with or without line
+// breaks.
+func decodeDKIMPublicKey(key string) ([]byte, error) {
+	var der []byte
+	if strings.Contains(key, "-----BEGIN") {
+		block, _ := pem.Decode([]byte(strings.TrimSpace(key)))
+		if block == nil {
+			return nil, fmt.Errorf("not a valid PEM encoded key")
+		}
+
+		switch block.Type {
+		case "PUBLIC KEY":
+			der = block.Bytes
+		case "RSA PUBLIC KEY":
+			rsaKey, err := x509.ParsePKCS1PublicKey(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("not a valid RSA public key: %w", err)
+			}
+			if der, err = x509.MarshalPKIXPublicKey(rsaKey); err != nil {
+				return nil, err
+			}
+		default:
+			if strings.Contains(block.Type, "PRIVATE KEY") {
+				return nil, fmt.Errorf("got a private key, publish only the public key")
+			}
+			return nil, fmt.Errorf("expected a PEM encoded PUBLIC KEY, got %q", block.Type)
+		}
+	} else {
+		var err error
+		der, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(key), ""))
+		if err != nil {
+			return nil, fmt.Errorf("not valid base64: %w", err)
+		}
+	}
+
+	if len(der) == 0 {
+		return nil, fmt.Errorf("the key is empty")
+	}
+
+	if parsed, err := x509.ParsePKIXPublicKey(der); err == nil {
+		if ed25519Key, ok := parsed.(ed25519.PublicKey); ok {
+			return ed25519Key, nil
+		}
+	}
+
+	return der, nil
+}
+
+// normalizeDKIMPublicKey returns the key base64 encoded as published in a DKIM
+// record, or as is if it isn't valid.
+func normalizeDKIMPublicKey(key string) string {
+	der, err := decodeDKIMPublicKey(key)
+	if err != nil {
+		return key
+	}
+	return base64.StdEncoding.EncodeToString(der)
+}
+
+func normalizeDKIMPublicKeyState(v interface{}) string {
+	return normalizeDKIMPublicKey(v.(string))
+}
+
+func validateDKIMPublicKey(v interface{}, key string) ([]string, []error) {
+	if _, err := decodeDKIMPublicKey(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", key, err)}
+	}
+
+	return nil, nil
+}
+
+// validateDKIMSelector checks a selector, one or more labels put in front of
+// _domainkey.
+func validateDKIMSelector(v interface{}, key string) ([]string, []error) {
+	if value := v.(string); !isHostname(value) || strings.HasSuffix(value, ".") {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid selector", key, value)}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,160 @@
+package domeneshop_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"math/big"
+	"regexp"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestAccDKIMKey_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsaDER, _ := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	rsaPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: rsaDER}))
+
+	ed25519Key, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519DER, _ := x509.MarshalPKIXPublicKey(ed25519Key)
+	ed25519PEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: ed25519DER}))
+
+	var recordId int
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dkim_key", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id":  domain.Id,
+				"selector":   "2024q1",
+				"public_key": rsaPEM,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				expected := "v=DKIM1; k=rsa; p=" + base64.StdEncoding.EncodeToString(rsaDER)
+				if len(records) != 1 || records[0].Host != "2024q1._domainkey" || records[0].Type != "TXT" {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if !strings.HasPrefix(records[0].Data, `"v=DKIM1; k=rsa; p=`) {
+					return fmt.Errorf("expected the record to be split into quoted strings, got %q", records[0].Data)
+				}
+				if state.Attributes["value"] != expected {
+					return fmt.Errorf("unexpected value %q", state.Attributes["value"])
+				}
+				if state.Attributes["public_key"] != base64.StdEncoding.EncodeToString(rsaDER) {
+					return fmt.Errorf("unexpected public_key %q", state.Attributes["public_key"])
+				}
+				recordId = records[0].Id
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id":  domain.Id,
+				"selector":   "2024q1",
+				"key_type":   "ed25519",
+				"public_key": ed25519PEM,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				expected := "v=DKIM1; k=ed25519; p=" + base64.StdEncoding.EncodeToString(ed25519Key)
+				if len(records) != 1 || records[0].Data != expected {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if records[0].Id != recordId {
+					return fmt.Errorf("expected record %d to be updated in place, got %d", recordId, records[0].Id)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id":  domain.Id,
+				"selector":   "2024q2",
+				"key_type":   "ed25519",
+				"public_key": base64.StdEncoding.EncodeToString(ed25519Key),
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Host != "2024q2._domainkey" || records[0].Id == recordId {
+					return fmt.Errorf("expected a new record at the new selector, got %+v", records)
+				}
+				return nil
+			},
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("records still exist: %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDKIMKey_invalid(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	existing := server.AddRecord(domain.Id, model.DnsRecord{Host: "taken._domainkey", Type: "TXT", Data: "v=DKIM1; k=ed25519; p=11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=", Ttl: 3600})
+
+	ed25519Key, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ed25519Base64 := base64.StdEncoding.EncodeToString(ed25519Key)
+	privateDER, _ := x509.MarshalPKCS8PrivateKey(privateKey)
+	privatePEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}))
+
+	shortN := new(big.Int).Lsh(big.NewInt(1), 511)
+	shortDER, _ := x509.MarshalPKIXPublicKey(&rsa.PublicKey{N: shortN.Add(shortN, big.NewInt(1)), E: 65537})
+	shortBase64 := base64.StdEncoding.EncodeToString(shortDER)
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"selector": {map[string]interface{}{"selector": "s 1", "key_type": "ed25519", "public_key": ed25519Base64}, "not a valid selector"},
+		"key_type": {map[string]interface{}{"selector": "s1", "key_type": "dsa", "public_key": ed25519Base64}, "is not one of"},
+		"base64":   {map[string]interface{}{"selector": "s1", "public_key": "not base64!"}, "not valid base64"},
+		"private":  {map[string]interface{}{"selector": "s1", "public_key": privatePEM}, "got a private key"},
+		"mismatch": {map[string]interface{}{"selector": "s1", "public_key": ed25519Base64}, `not an RSA public key, but key_type is "rsa"`},
+		"short":    {map[string]interface{}{"selector": "s1", "public_key": shortBase64}, "RSA key of 512 bits is shorter than the 1024 bits"},
+		"exists": {
+			map[string]interface{}{"selector": "taken", "key_type": "ed25519", "public_key": ed25519Base64},
+			fmt.Sprintf("already exists as DNS record %d/%d", domain.Id, existing),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["domain_id"] = domain.Id
+			testAccResource(t, testAccProvider(t, server), "domeneshop_dkim_key", []testAccStep{
+				{
+					Config:      tc.config,
+					ExpectError: regexp.MustCompile(regexp.QuoteMeta(tc.err)),
+				},
+			}, nil)
+		})
+	}
+}
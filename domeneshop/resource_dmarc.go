@@ -0,0 +1,210 @@
+package domeneshop
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"strings"
+)
+
+// dmarcHost is where the DMARC policy of a domain is published.
+const dmarcHost = "_dmarc"
+
+var dmarcPolicies = []string{"none", "quarantine", "reject"}
+
+func resourceDMARC() *schema.Resource {
+	return dmarcRecordKind().resource()
+}
+
+func dmarcRecordKind() *txtRecordKind {
+	return &txtRecordKind{
+		name: "DMARC record",
+		tag:  "v=DMARC1",
+		arguments: map[string]*schema.Schema{
+			"policy": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateOneOf(dmarcPolicies...),
+			},
+			"subdomain_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateOneOf(dmarcPolicies...),
+			},
+			"pct": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				ValidateFunc: validateIntBetween(0, 100),
+			},
+			"rua": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDMARCURI,
+				},
+			},
+			"ruf": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateDMARCURI,
+				},
+			},
+			"adkim": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateOneOf("r", "s"),
+			},
+			"aspf": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateOneOf("r", "s"),
+			},
+			"fo": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDMARCFailureOptions,
+			},
+		},
+		host: func(get func(key string) interface{}) string {
+			return dmarcHost
+		},
+		render:  renderDMARCRecord,
+		flatten: flattenDMARCRecord,
+	}
+}
+
+// renderDMARCRecord returns the DMARC policy of the arguments, leaving out
+// tags with their default value.
+func renderDMARCRecord(get func(key string) interface{}) string {
+	tags := []string{"v=DMARC1", "p=" + get("policy").(string)}
+
+	if value := get("subdomain_policy").(string); value != "" {
+		tags = append(tags, "sp="+value)
+	}
+	if value := get("pct").(int); value != 100 {
+		tags = append(tags, "pct="+strconv.Itoa(value))
+	}
+	for _, key := range []string{"rua", "ruf"} {
+		var uris []string
+		for _, uri := range get(key).([]interface{}) {
+			value, _ := uri.(string)
+			uris = append(uris, value)
+		}
+		if len(uris) > 0 {
+			tags = append(tags, key+"="+strings.Join(uris, ","))
+		}
+	}
+	for _, key := range []string{"adkim", "aspf", "fo"} {
+		if value := get(key).(string); value != "" {
+			tags = append(tags, key+"="+value)
+		}
+	}
+
+	return strings.Join(tags, "; ")
+}
+
+// flattenDMARCRecord returns the arguments of a DMARC policy, or false if it
+// isn't one or has tags they don't express.
+func flattenDMARCRecord(_, data string) (map[string]interface{}, bool) {
+	var tags []string
+	for _, tag := range strings.Split(data, ";") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) < 2 || tags[0] != "v=DMARC1" {
+		return nil, false
+	}
+
+	arguments := map[string]interface{}{
+		"policy":           "",
+		"subdomain_policy": "",
+		"pct":              100,
+		"rua":              []interface{}{},
+		"ruf":              []interface{}{},
+		"adkim":            "",
+		"aspf":             "",
+		"fo":               "",
+	}
+	for _, tag := range tags[1:] {
+		i := strings.IndexByte(tag, '=')
+		if i < 0 {
+			return nil, false
+		}
+		key, value := strings.TrimSpace(tag[:i]), strings.TrimSpace(tag[i+1:])
+
+		switch key {
+		case "p":
+			arguments["policy"] = strings.ToLower(value)
+		case "sp":
+			arguments["subdomain_policy"] = strings.ToLower(value)
+		case "pct":
+			pct, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, false
+			}
+			arguments["pct"] = pct
+		case "rua", "ruf":
+			uris := []interface{}{}
+			for _, uri := range strings.Split(value, ",") {
+				uris = append(uris, strings.TrimSpace(uri))
+			}
+			arguments[key] = uris
+		case "adkim", "aspf", "fo":
+			arguments[key] = strings.ToLower(value)
+		default:
+			return nil, false
+		}
+	}
+
+	return arguments, arguments["policy"] != ""
+}
+
+// validateDMARCURI checks a report address, a mailto: URI optionally followed
+// by a size limit like !10m.
+func validateDMARCURI(v interface{}, key string) ([]string, []error) {
+	value := v.(string)
+
+	address := strings.TrimPrefix(value, "mailto:")
+	if address == value {
+		if strings.Contains(value, "@") {
+			return nil, []error{fmt.Errorf("%s: %q is not a URI, use \"mailto:%s\"", key, value, value)}
+		}
+		return nil, []error{fmt.Errorf("%s: %q is not a mailto: URI", key, value)}
+	}
+
+	if i := strings.IndexByte(address, '!'); i >= 0 {
+		limit := address[i+1:]
+		if n := len(limit); n > 0 && strings.IndexByte("kmgt", limit[n-1]) >= 0 {
+			limit = limit[:n-1]
+		}
+		if _, err := strconv.Atoi(limit); err != nil {
+			return nil, []error{fmt.Errorf("%s: %q has an invalid size limit, expected i.e. !10m", key, value)}
+		}
+		address = address[:i]
+	}
+
+	at := strings.LastIndexByte(address, '@')
+	if at <= 0 || !isHostname(address[at+1:]) || strings.ContainsAny(address, ",; ") {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid email address", key, value)}
+	}
+
+	return nil, nil
+}
+
+// validateDMARCFailureOptions checks fo, a colon separated list of 0, 1, d
+// and s.
+func validateDMARCFailureOptions(v interface{}, key string) ([]string, []error) {
+	value := v.(string)
+	for _, option := range strings.Split(value, ":") {
+		if !stringInSlice(option, []string{"0", "1", "d", "s"}) {
+			return nil, []error{fmt.Errorf("%s: %q is not a colon separated list of 0, 1, d and s", key, value)}
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,166 @@
+package domeneshop_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestAccDMARC_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	var recordId int
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dmarc", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"policy":    "reject",
+				"rua":       []interface{}{"mailto:dmarc@example.com"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				expected := "v=DMARC1; p=reject; rua=mailto:dmarc@example.com"
+				if len(records) != 1 || records[0].Host != "_dmarc" || records[0].Type != "TXT" || records[0].Data != expected {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if state.Attributes["value"] != expected {
+					return fmt.Errorf("unexpected value %q", state.Attributes["value"])
+				}
+				recordId = records[0].Id
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id":        domain.Id,
+				"policy":           "quarantine",
+				"subdomain_policy": "none",
+				"pct":              50,
+				"rua":              []interface{}{"mailto:dmarc@example.com", "mailto:reports@example.net!10m"},
+				"ruf":              []interface{}{"mailto:forensic@example.com"},
+				"adkim":            "s",
+				"aspf":             "r",
+				"fo":               "1:d",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				expected := "v=DMARC1; p=quarantine; sp=none; pct=50; rua=mailto:dmarc@example.com,mailto:reports@example.net!10m; " +
+					"ruf=mailto:forensic@example.com; adkim=s; aspf=r; fo=1:d"
+				if len(records) != 1 || records[0].Data != expected {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if records[0].Id != recordId {
+					return fmt.Errorf("expected record %d to be updated in place, got %d", recordId, records[0].Id)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("records still exist: %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDMARC_import(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	cases := map[string]struct {
+		data     string
+		expected map[string]string
+	}{
+		"parsed": {
+			"v=DMARC1;p=Quarantine; pct=25;rua=mailto:a@example.com, mailto:b@example.org; fo=s",
+			map[string]string{
+				"policy": "quarantine",
+				"pct":    "25",
+				"rua.#":  "2",
+				"rua.0":  "mailto:a@example.com",
+				"rua.1":  "mailto:b@example.org",
+				"fo":     "s",
+				"value":  "v=DMARC1;p=Quarantine; pct=25;rua=mailto:a@example.com, mailto:b@example.org; fo=s",
+			},
+		},
+		"unknown tag": {
+			"v=DMARC1; p=none; ri=86400",
+			map[string]string{
+				"policy": "",
+				"value":  "v=DMARC1; p=none; ri=86400",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			provider := testAccProvider(t, server)
+			recordId := server.AddRecord(domain.Id, model.DnsRecord{Host: "_dmarc", Type: "TXT", Data: tc.data, Ttl: 3600})
+			id := fmt.Sprintf("%d/%d", domain.Id, recordId)
+
+			imported, err := provider.ImportState(ctx, &terraform.InstanceInfo{Type: "domeneshop_dmarc"}, id)
+			if err != nil {
+				t.Fatalf("import %s: %v", id, err)
+			}
+			state, err := testAccRefresh(ctx, provider.ResourcesMap["domeneshop_dmarc"], imported[0], provider.Meta())
+			if err != nil {
+				t.Fatalf("import %s: refresh: %v", id, err)
+			}
+
+			for key, expected := range tc.expected {
+				if actual := state.Attributes[key]; actual != expected {
+					t.Errorf("attribute %s: expected %q, got %q", key, expected, actual)
+				}
+			}
+		})
+	}
+}
+
+func TestAccDMARC_invalid(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"policy":  {map[string]interface{}{"policy": "deny"}, "is not one of"},
+		"pct":     {map[string]interface{}{"policy": "none", "pct": 101}, "must be between 0 and 100"},
+		"rua":     {map[string]interface{}{"policy": "none", "rua": []interface{}{"dmarc@example.com"}}, `use "mailto:dmarc@example.com"`},
+		"ruf":     {map[string]interface{}{"policy": "none", "ruf": []interface{}{"https://example.com/"}}, "not a mailto: URI"},
+		"limit":   {map[string]interface{}{"policy": "none", "rua": []interface{}{"mailto:dmarc@example.com!10x"}}, "invalid size limit"},
+		"address": {map[string]interface{}{"policy": "none", "rua": []interface{}{"mailto:example.com"}}, "not a valid email address"},
+		"adkim":   {map[string]interface{}{"policy": "none", "adkim": "strict"}, "is not one of"},
+		"fo":      {map[string]interface{}{"policy": "none", "fo": "2"}, "not a colon separated list"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["domain_id"] = domain.Id
+			testAccResource(t, testAccProvider(t, server), "domeneshop_dmarc", []testAccStep{
+				{
+					Config:      tc.config,
+					ExpectError: regexp.MustCompile(regexp.QuoteMeta(tc.err)),
+				},
+			}, nil)
+		})
+	}
+}
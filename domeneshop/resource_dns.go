@@ -1,75 +1,61 @@
 package domeneshop
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"io"
-	"io/ioutil"
-	"log"
-	"net/http"
 	"strconv"
 	"strings"
-	"terraform-provider-domeneshop/domeneshop/api"
+	"terraform-provider-domeneshop/domeneshop/client"
 	"terraform-provider-domeneshop/domeneshop/model"
 	"time"
 )
 
-type IdResponse struct {
-	Id int `json:"id"`
-}
-
 func resourceDNSRecord() *schema.Resource {
-	return &schema.Resource{
+	resource := &schema.Resource{
 		CreateContext: resourceDNSRecordCreate,
 		ReadContext:   resourceDNSRecordRead,
 		UpdateContext: resourceDNSUpdate,
 		DeleteContext: resourceDNSRecordDelete,
+		CustomizeDiff: resourceDNSRecordCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceDNSRecordState,
 		},
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceDNSRecordV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: resourceDNSRecordStateUpgradeV0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"domain_id": {
 				Type:         schema.TypeInt,
 				Optional:     true,
-				RequiredWith: []string{"type", "host", "data"},
+				RequiredWith: []string{"type", "host"},
 				ForceNew:     true,
 			},
 			"ttl": {
-				Type:     schema.TypeInt,
-				Optional: true,
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateFunc:     validateTTL,
+				DiffSuppressFunc: suppressDefaultTTL,
 			},
 			"type": {
 				Type:         schema.TypeString,
 				ForceNew:     true,
 				Optional:     true,
-				RequiredWith: []string{"domain_id", "host", "data"},
+				RequiredWith: []string{"domain_id", "host"},
+				ValidateFunc: validateDNSRecordType,
 			},
 			"host": {
 				Type:         schema.TypeString,
 				Optional:     true,
-				RequiredWith: []string{"domain_id", "type", "data"},
+				RequiredWith: []string{"domain_id", "type"},
 				ForceNew:     true,
-			},
-			"data": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				RequiredWith: []string{"domain_id", "type", "host"},
-			},
-			"priority": {
-				Type:     schema.TypeString,
-				Optional: true,
-			},
-			"weight": {
-				Type:     schema.TypeInt,
-				Optional: true,
-			},
-			"port": {
-				Type:     schema.TypeInt,
-				Optional: true,
+				StateFunc:    normalizeDNSHostState,
 			},
 			"last_updated": {
 				Type:     schema.TypeString,
@@ -78,254 +64,395 @@ func resourceDNSRecord() *schema.Resource {
 			},
 		},
 	}
+
+	for key, field := range dnsRecordFieldsSchema() {
+		resource.Schema[key] = field
+	}
+
+	return resource
+}
+
+// dnsRecordFieldsSchema returns the schema of the contents of a record, shared
+// by domeneshop_dns_record, the record blocks of domeneshop_dns_zone and the
+// records of the domeneshop_dns_records data source. Which fields apply
+// depends on the record type.
+func dnsRecordFieldsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"data": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateFunc:     validateTXTUDPLength,
+			DiffSuppressFunc: suppressEquivalentDNSData,
+		},
+		"priority": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateNumericString(0, 65535),
+		},
+		"weight": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 65535),
+		},
+		"port": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(1, 65535),
+		},
+		"flags": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 255),
+		},
+		"tag": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateCAATag,
+		},
+		"value": {
+			Type:     schema.TypeString,
+			Optional: true,
+		},
+		"key_tag": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 65535),
+		},
+		"alg": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 255),
+		},
+		"digest_type": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 255),
+		},
+		"digest": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validateHex,
+		},
+		"usage": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 3),
+		},
+		"selector": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 1),
+		},
+		"dtype": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validateIntBetween(0, 2),
+		},
+	}
 }
 
 func resourceDNSRecordState(ctx context.Context, d *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
-	parts := strings.SplitN(d.Id(), "/", 2)
-	domainId, err := strconv.Atoi(parts[0])
+	domainId, _, err := parseDNSRecordId(d.Id())
 	if err != nil {
 		return nil, err
 	}
 
-	recordId := parts[1]
-
 	err = d.Set("domain_id", domainId)
 	if err != nil {
 		return nil, err
 	}
 
-	d.SetId(recordId)
 	return []*schema.ResourceData{d}, nil
 }
 
 func resourceDNSRecordCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// Warning or errors can be collected in a slice type
-	var diags diag.Diagnostics
-
-	client := m.(*http.Client)
-
-	domainId := d.Get("domain_id").(int)
-
-	log.Printf("DOMAIN_RECORDS: %s", api.DNSRecords(domainId))
-
 	record, err := dnsRecordFromSchema(d)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
-	buffer := new(bytes.Buffer)
-	err = json.NewEncoder(buffer).Encode(record)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	response, err := client.Post(api.DNSRecords(domainId), "application/json", buffer)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer closeBody(response.Body)
-
-	switch response.StatusCode {
-	case 201:
-		var parsed IdResponse
-		err := json.NewDecoder(response.Body).Decode(&parsed)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-
-		d.SetId(api.DNSRecord(domainId, parsed.Id))
-
-		// refresh state
-		diags = append(diags, resourceDNSRecordRead(ctx, d, m)...)
-	default:
-		b, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		return []diag.Diagnostic{{
-			Severity: diag.Error,
-			Summary:  "unexpected status code from create operation",
-
-			Detail: fmt.Sprintf("expected 201, got %d. Response : %v", response.StatusCode, string(b)),
-		}}
+	diags := createDNSRecord(ctx, d, m, "DNS record", record)
+	if diags.HasError() {
+		return diags
 	}
 
-	return diags
+	// refresh state
+	return append(diags, resourceDNSRecordRead(ctx, d, m)...)
 }
 
 func resourceDNSRecordRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-
-	client := m.(*http.Client)
-
-	recordId, err := strconv.Atoi(d.Id())
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	domainId := d.Get("domain_id").(int)
-
-	response, err := client.Get(api.DNSRecord(domainId, recordId))
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	defer closeBody(response.Body)
-
-	var record model.DnsRecord
-	err = json.NewDecoder(response.Body).Decode(&record)
-	if err != nil {
-		return diag.FromErr(err)
+	record, diags := readDNSRecord(ctx, d, m, "DNS record")
+	if record == nil {
+		return diags
 	}
 
 	var errs []error
-	errs = append(errs, d.Set("domain_id", domainId))
 	errs = append(errs, d.Set("type", record.Type))
-	errs = append(errs, d.Set("host", record.Host))
-	errs = append(errs, d.Set("data", record.Data))
-	errs = append(errs, d.Set("ttl", record.Ttl))
+	errs = append(errs, d.Set("host", normalizeDNSHost(record.Host)))
 
-	switch record.Type {
-	case "SRV":
-		errs = append(errs, d.Set("priority", record.Priority))
-		errs = append(errs, d.Set("port", record.Port))
-		errs = append(errs, d.Set("weight", record.Weight))
-	case "MX":
-		errs = append(errs, d.Set("priority", record.Priority))
+	for key, value := range flattenDNSRecordFields(record) {
+		errs = append(errs, d.Set(key, value))
 	}
 
-	for _, err = range errs {
+	for _, err := range errs {
 		if err != nil {
 			diags = append(diags, diag.FromErr(err)...)
 		}
 	}
 
-	if diags != nil && diags.HasError() {
-		return diags
-	}
-
 	return diags
 }
 
 func resourceDNSUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*http.Client)
-
-	recordURI := d.Id()
+	var diags diag.Diagnostics
 
-	if d.HasChanges("type", "data", "priority", "weight", "host", "ttl", "port") {
+	if d.HasChanges("type", "data", "priority", "weight", "host", "ttl", "port", "flags", "tag", "value", "key_tag", "alg", "digest_type", "digest", "usage", "selector", "dtype") {
 		dnsRecord, err := dnsRecordFromSchema(d)
 		if err != nil {
 			return diag.FromErr(err)
 		}
 
-		buffer := new(bytes.Buffer)
-		err = json.NewEncoder(buffer).Encode(dnsRecord)
-		if err != nil {
-			return diag.FromErr(err)
+		diags = updateDNSRecord(ctx, d, m, "DNS record", dnsRecord)
+		if diags.HasError() {
+			return diags
 		}
 
-		request, err := http.NewRequest("PUT", recordURI, buffer)
+		err = d.Set("last_updated", time.Now().Format(time.RFC850))
 		if err != nil {
 			return diag.FromErr(err)
 		}
+	}
 
-		response, err := client.Do(request)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		defer closeBody(response.Body)
-
-		if response.StatusCode != 204 {
-			b, err := ioutil.ReadAll(response.Body)
-			if err != nil {
-				return diag.FromErr(err)
-			}
-			return []diag.Diagnostic{{
-				Severity: diag.Error,
-				Summary:  "unexpected status code during update operation",
-
-				Detail: fmt.Sprintf("expected 204, got %d. Response : %v", response.StatusCode, string(b)),
-			}}
-		}
+	return append(diags, resourceDNSRecordRead(ctx, d, m)...)
+}
 
-		err = d.Set("last_updated", time.Now().Format(time.RFC850))
+func resourceDNSRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteDNSRecord(ctx, d, m, "DNS record")
+}
+
+// createDNSRecord creates record in the domain of d and sets the id of d. The
+// resource managing it is called what in messages. Resources managing a
+// single record share this and the helpers below.
+func createDNSRecord(ctx context.Context, d *schema.ResourceData, m interface{}, what string, record *model.DnsRecord) diag.Diagnostics {
+	client := m.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+
+	recordId, err := client.CreateRecord(ctx, domainId, record)
+	if err != nil {
+		return apiError(fmt.Sprintf("creating %s", what), err)
+	}
+
+	d.SetId(dnsRecordId(domainId, recordId))
+
+	return nil
+}
+
+// readDNSRecord returns the record of d, setting domain_id and ttl from it.
+// It returns no record if there is an error, or if the record is gone and d
+// has been removed from the state.
+func readDNSRecord(ctx context.Context, d *schema.ResourceData, m interface{}, what string) (*model.DnsRecord, diag.Diagnostics) {
+	client := m.(client.API)
+
+	domainId, recordId, err := parseDNSRecordId(d.Id())
+	if err != nil {
+		return nil, diag.FromErr(err)
+	}
+
+	record, err := client.GetRecord(ctx, domainId, recordId)
+	if err != nil {
+		return nil, readError(d, what, err)
+	}
+
+	var diags diag.Diagnostics
+	for _, err = range []error{d.Set("domain_id", domainId), d.Set("ttl", record.Ttl)} {
 		if err != nil {
-			return diag.FromErr(err)
+			diags = append(diags, diag.FromErr(err)...)
 		}
 	}
 
-	return resourceDNSRecordRead(ctx, d, m)
+	return record, diags
 }
 
-func resourceDNSRecordDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	var diags diag.Diagnostics
-	recordURI := d.Id()
+// updateDNSRecord replaces the record of d with record.
+func updateDNSRecord(ctx context.Context, d *schema.ResourceData, m interface{}, what string, record *model.DnsRecord) diag.Diagnostics {
+	client := m.(client.API)
 
-	client := m.(*http.Client)
-	request, err := http.NewRequest("DELETE", recordURI, nil)
+	domainId, recordId, err := parseDNSRecordId(d.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
+	record.Id = recordId
 
-	response, err := client.Do(request)
+	err = client.UpdateRecord(ctx, domainId, record)
 	if err != nil {
-		return diag.FromErr(err)
+		return apiError(fmt.Sprintf("updating %s", what), err)
 	}
 
-	if response.StatusCode != 204 {
-		b, err := ioutil.ReadAll(response.Body)
-		if err != nil {
-			return diag.FromErr(err)
-		}
-		return []diag.Diagnostic{{
-			Severity: diag.Error,
-			Summary:  "unexpected status code during delete operation",
+	return nil
+}
+
+// deleteDNSRecord deletes the record of d.
+func deleteDNSRecord(ctx context.Context, d *schema.ResourceData, m interface{}, what string) diag.Diagnostics {
+	client := m.(client.API)
+
+	domainId, recordId, err := parseDNSRecordId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
-			Detail: fmt.Sprintf("expected 204, got %d. Response : %v", response.StatusCode, string(b)),
-		}}
+	err = client.DeleteRecord(ctx, domainId, recordId)
+	if err != nil {
+		return deleteError(what, err)
 	}
 
-	return diags
+	return nil
 }
 
 func dnsRecordFromSchema(d *schema.ResourceData) (*model.DnsRecord, error) {
-	recordType := d.Get("type").(string)
+	return dnsRecordFromFields(d.Get)
+}
+
+// dnsRecordFromFields builds a record from the fields in
+// dnsRecordFieldsSchema, plus type, host and ttl, as returned by get.
+func dnsRecordFromFields(get func(key string) interface{}) (*model.DnsRecord, error) {
+	recordType := get("type").(string)
 	record := model.DnsRecord{
 		Type: recordType,
-		Host: d.Get("host").(string),
-		Ttl:  d.Get("ttl").(int),
-		Data: d.Get("data").(string),
+		Host: normalizeDNSHost(get("host").(string)),
+		Ttl:  get("ttl").(int),
+		Data: normalizeDNSRecordData(recordType, get("data").(string)),
 	}
 
 	switch recordType {
 	case "SRV":
-		if priority, ok := d.GetOk("priority"); ok {
-			record.Priority = priority.(string)
-		} else {
+		record.Priority = get("priority").(string)
+		if record.Priority == "" {
 			return nil, fmt.Errorf("%s is required for %s record", "priority", recordType)
 		}
 
-		if weight, ok := d.GetOk("weight"); ok {
-			record.Weight = weight.(int)
-		} else {
-			return nil, fmt.Errorf("%s is required for %s record", "weight", recordType)
-		}
+		// A weight of 0 is valid, so it can't be told apart from unset.
+		record.Weight = get("weight").(int)
 
-		if port, ok := d.GetOk("port"); ok {
-			record.Port = port.(int)
-		} else {
+		record.Port = get("port").(int)
+		if record.Port == 0 {
 			return nil, fmt.Errorf("%s is required for %s record", "port", recordType)
 		}
 	case "MX":
-		if priority, ok := d.GetOk("priority"); ok {
-			record.Priority = priority.(string)
-		} else {
+		record.Priority = get("priority").(string)
+		if record.Priority == "" {
 			return nil, fmt.Errorf("%s is required for %s record", "priority", recordType)
 		}
+	case "CAA":
+		tag, err := caaTagValue(get("tag").(string))
+		if err != nil {
+			return nil, err
+		}
+		record.Data = get("value").(string)
+		record.Flags = intPointer(get("flags").(int))
+		record.Tag = intPointer(tag)
+	case "DS":
+		record.Data = get("digest").(string)
+		record.Tag = intPointer(get("key_tag").(int))
+		record.Alg = intPointer(get("alg").(int))
+		record.Digest = intPointer(get("digest_type").(int))
+	case "TXT":
+		record.Data = encodeTXTData(record.Data)
+	case "TLSA":
+		record.Usage = intPointer(get("usage").(int))
+		record.Selector = intPointer(get("selector").(int))
+		record.Dtype = intPointer(get("dtype").(int))
 	}
 
 	return &record, nil
 }
 
-func closeBody(body io.ReadCloser) {
-	if err := body.Close(); err != nil {
-		log.Printf("closing body: %v\n", err)
+// flattenDNSRecordFields returns the fields in dnsRecordFieldsSchema that
+// apply to the type of record, with data normalized.
+func flattenDNSRecordFields(record *model.DnsRecord) map[string]interface{} {
+	data := normalizeDNSRecordData(record.Type, record.Data)
+
+	switch record.Type {
+	case "SRV":
+		return map[string]interface{}{
+			"data":     data,
+			"priority": record.Priority,
+			"port":     record.Port,
+			"weight":   record.Weight,
+		}
+	case "MX":
+		return map[string]interface{}{
+			"data":     data,
+			"priority": record.Priority,
+		}
+	case "CAA":
+		return map[string]interface{}{
+			"value": record.Data,
+			"flags": model.IntValue(record.Flags),
+			"tag":   caaTagName(model.IntValue(record.Tag)),
+		}
+	case "DS":
+		return map[string]interface{}{
+			"digest":      record.Data,
+			"key_tag":     model.IntValue(record.Tag),
+			"alg":         model.IntValue(record.Alg),
+			"digest_type": model.IntValue(record.Digest),
+		}
+	case "TLSA":
+		return map[string]interface{}{
+			"data":     data,
+			"usage":    model.IntValue(record.Usage),
+			"selector": model.IntValue(record.Selector),
+			"dtype":    model.IntValue(record.Dtype),
+		}
+	default:
+		return map[string]interface{}{
+			"data": data,
+		}
+	}
+}
+
+// caaTagValue converts a CAA tag name to the number the API uses for it.
+func caaTagValue(name string) (int, error) {
+	for value, tag := range model.CAATags {
+		if tag == name {
+			return value, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown CAA tag %q, expected one of %s", name, strings.Join(model.CAATags, ", "))
+}
+
+func caaTagName(value int) string {
+	if value < 0 || value >= len(model.CAATags) {
+		return strconv.Itoa(value)
 	}
+	return model.CAATags[value]
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+// dnsRecordId builds the `domain_id/record_id` ID of a DNS record.
+func dnsRecordId(domainId, recordId int) string {
+	return fmt.Sprintf("%d/%d", domainId, recordId)
+}
+
+func parseDNSRecordId(id string) (int, int, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("unexpected format of ID (%s), expected domain_id/record_id", id)
+	}
+
+	domainId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected format of ID (%s), expected domain_id/record_id: %w", id, err)
+	}
+
+	recordId, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("unexpected format of ID (%s), expected domain_id/record_id: %w", id, err)
+	}
+
+	return domainId, recordId, nil
 }
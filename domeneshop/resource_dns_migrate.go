@@ -0,0 +1,101 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"strings"
+)
+
+// resourceDNSRecordV0 is the schema of domeneshop_dns_record before IDs were
+// made `domain_id/record_id`.
+func resourceDNSRecordV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"ttl": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"data": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"priority": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"weight": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"port": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"last_updated": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceDNSRecordStateUpgradeV0 rewrites the ID of version 0 states, which
+// is either the record URL (created records) or the bare record ID (imported
+// records), to `domain_id/record_id`.
+func resourceDNSRecordStateUpgradeV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	id, _ := rawState["id"].(string)
+
+	// i.e. https://api.domeneshop.no/v0/domains/1337/dns/1338
+	if parts := strings.Split(strings.TrimSuffix(id, "/"), "/"); len(parts) >= 4 && parts[len(parts)-2] == "dns" && parts[len(parts)-4] == "domains" {
+		domainId, err := strconv.Atoi(parts[len(parts)-3])
+		if err != nil {
+			return nil, fmt.Errorf("upgrading DNS record ID %s: %w", id, err)
+		}
+		recordId, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			return nil, fmt.Errorf("upgrading DNS record ID %s: %w", id, err)
+		}
+
+		rawState["id"] = dnsRecordId(domainId, recordId)
+		rawState["domain_id"] = domainId
+		return rawState, nil
+	}
+
+	recordId, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, fmt.Errorf("upgrading DNS record ID %s: unexpected format", id)
+	}
+
+	var domainId int
+	switch value := rawState["domain_id"].(type) {
+	case float64:
+		domainId = int(value)
+	case int:
+		domainId = value
+	case string:
+		domainId, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("upgrading DNS record ID %s: domain_id: %w", id, err)
+		}
+	default:
+		return nil, fmt.Errorf("upgrading DNS record ID %s: domain_id is not set", id)
+	}
+
+	rawState["id"] = dnsRecordId(domainId, recordId)
+	return rawState, nil
+}
@@ -0,0 +1,49 @@
+package domeneshop
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResourceDNSRecordStateUpgradeV0(t *testing.T) {
+	cases := map[string]struct {
+		rawState map[string]interface{}
+		id       string
+		domainId interface{}
+	}{
+		"created": {
+			rawState: map[string]interface{}{"id": "https://api.domeneshop.no/v0/domains/1337/dns/1338", "domain_id": float64(1337)},
+			id:       "1337/1338",
+			domainId: 1337,
+		},
+		"created with custom endpoint": {
+			rawState: map[string]interface{}{"id": "http://localhost:8080/domains/1337/dns/1338"},
+			id:       "1337/1338",
+			domainId: 1337,
+		},
+		"imported": {
+			rawState: map[string]interface{}{"id": "1338", "domain_id": float64(1337)},
+			id:       "1337/1338",
+			domainId: float64(1337),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			upgraded, err := resourceDNSRecordStateUpgradeV0(context.Background(), tc.rawState, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if upgraded["id"] != tc.id {
+				t.Errorf("expected id %s, got %v", tc.id, upgraded["id"])
+			}
+			if upgraded["domain_id"] != tc.domainId {
+				t.Errorf("expected domain_id %v, got %v", tc.domainId, upgraded["domain_id"])
+			}
+		})
+	}
+
+	if _, err := resourceDNSRecordStateUpgradeV0(context.Background(), map[string]interface{}{"id": "1338"}, nil); err == nil {
+		t.Error("expected an error when domain_id is missing")
+	}
+}
@@ -0,0 +1,140 @@
+package domeneshop
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// defaultDNSRecordTTL is the TTL the API gives records created without one.
+const defaultDNSRecordTTL = 3600
+
+// normalizeDNSHost returns host in the form the API stores it: lower case,
+// without a trailing dot, and @ for the domain itself.
+func normalizeDNSHost(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" {
+		return "@"
+	}
+	return host
+}
+
+// normalizeDNSRecordData returns data in a canonical form for its record type,
+// so equivalent values compare equal: hostnames in lower case without a
+// trailing dot, IPv6 addresses compressed, and TXT data without the quotes of
+// zone file syntax.
+func normalizeDNSRecordData(recordType, data string) string {
+	switch recordType {
+	case "A":
+		return strings.TrimSpace(data)
+	case "AAAA":
+		if ip := net.ParseIP(strings.TrimSpace(data)); ip != nil && ip.To4() == nil {
+			return ip.String()
+		}
+		return data
+	case "ANAME", "CNAME", "MX", "NS", "SRV":
+		return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(data)), ".")
+	case "TXT":
+		if unquoted, ok := unquoteTXT(data); ok {
+			return unquoted
+		}
+		return data
+	default:
+		return data
+	}
+}
+
+// unquoteTXT joins the strings of TXT data written as one or more quoted
+// strings, like in a zone file, or returns false if data isn't written so.
+func unquoteTXT(data string) (string, bool) {
+	s := strings.TrimSpace(data)
+	if !strings.HasPrefix(s, `"`) {
+		return "", false
+	}
+
+	var unquoted strings.Builder
+	for s != "" {
+		if s[0] != '"' {
+			return "", false
+		}
+
+		i, closed := 1, false
+		for i < len(s) && !closed {
+			switch {
+			case s[i] == '\\' && i+3 < len(s) && isDigits(s[i+1:i+4]):
+				// \DDD, a byte by its decimal value.
+				unquoted.WriteByte(byte((s[i+1]-'0')*100 + (s[i+2]-'0')*10 + (s[i+3] - '0')))
+				i += 4
+			case s[i] == '\\' && i+1 < len(s):
+				unquoted.WriteByte(s[i+1])
+				i += 2
+			case s[i] == '"':
+				closed = true
+				i++
+			default:
+				unquoted.WriteByte(s[i])
+				i++
+			}
+		}
+		if !closed {
+			return "", false
+		}
+
+		s = strings.TrimLeft(s[i:], " \t")
+	}
+
+	return unquoted.String(), true
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeDNSHostState(v interface{}) string {
+	return normalizeDNSHost(v.(string))
+}
+
+// suppressEquivalentDNSData suppresses the diff of data that is equivalent for
+// the type of the record, which is read from the type field next to it.
+func suppressEquivalentDNSData(k, old, new string, d *schema.ResourceData) bool {
+	recordType, _ := d.Get(strings.TrimSuffix(k, "data") + "type").(string)
+	return normalizeDNSRecordData(recordType, old) == normalizeDNSRecordData(recordType, new)
+}
+
+// suppressDefaultTTL suppresses the diff of an unset TTL, when the record has
+// the TTL the API gives records created without one.
+func suppressDefaultTTL(k, old, new string, d *schema.ResourceData) bool {
+	return (new == "" || new == "0") && old == strconv.Itoa(defaultDNSRecordTTL)
+}
+
+// hashDNSRecordBlock returns a hash function for sets of record blocks of
+// resource, which hashes blocks with equivalent host and data alike. The type
+// of the record is read from the block, or else is recordType.
+func hashDNSRecordBlock(resource *schema.Resource, recordType string) schema.SchemaSetFunc {
+	hash := schema.HashResource(resource)
+	return func(v interface{}) int {
+		block := map[string]interface{}{}
+		for key, value := range v.(map[string]interface{}) {
+			block[key] = value
+		}
+
+		blockType := recordType
+		if value, ok := block["type"].(string); ok {
+			blockType = value
+		}
+		if host, ok := block["host"].(string); ok {
+			block["host"] = normalizeDNSHost(host)
+		}
+		if data, ok := block["data"].(string); ok {
+			block["data"] = normalizeDNSRecordData(blockType, data)
+		}
+
+		return hash(block)
+	}
+}
@@ -0,0 +1,46 @@
+package domeneshop
+
+import "testing"
+
+func TestNormalizeDNSRecordData(t *testing.T) {
+	cases := []struct {
+		recordType string
+		data       string
+		normalized string
+	}{
+		{"A", " 192.0.2.1 ", "192.0.2.1"},
+		{"AAAA", "2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"AAAA", "2001:DB8::1", "2001:db8::1"},
+		{"AAAA", "not an address", "not an address"},
+		{"CNAME", "Target.Example.com.", "target.example.com"},
+		{"MX", "mx.example.com.", "mx.example.com"},
+		{"NS", "ns1.hyp.net", "ns1.hyp.net"},
+		{"TXT", `"v=spf1 -all"`, "v=spf1 -all"},
+		{"TXT", `"first" "second \"part\""`, `firstsecond "part"`},
+		{"TXT", `v=spf1 "quoted" -all`, `v=spf1 "quoted" -all`},
+		{"TXT", `"unterminated`, `"unterminated`},
+		{"TLSA", "D2ABDE240D7CD3EE", "D2ABDE240D7CD3EE"},
+	}
+
+	for _, tc := range cases {
+		if normalized := normalizeDNSRecordData(tc.recordType, tc.data); normalized != tc.normalized {
+			t.Errorf("%s %q: expected %q, got %q", tc.recordType, tc.data, tc.normalized, normalized)
+		}
+	}
+}
+
+func TestNormalizeDNSHost(t *testing.T) {
+	cases := map[string]string{
+		"":      "@",
+		"@":     "@",
+		"WWW":   "www",
+		"www.":  "www",
+		"*.dev": "*.dev",
+	}
+
+	for host, normalized := range cases {
+		if got := normalizeDNSHost(host); got != normalized {
+			t.Errorf("%q: expected %q, got %q", host, normalized, got)
+		}
+	}
+}
@@ -0,0 +1,508 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// dnsRecordSetTypes are the record types a record set can hold. MX and SRV
+// records take record blocks, the others plain values.
+var dnsRecordSetTypes = []string{"A", "AAAA", "ANAME", "CNAME", "MX", "NS", "SRV", "TXT"}
+
+func resourceDNSRecordSet() *schema.Resource {
+	record := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"data": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"priority": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateNumericString(0, 65535),
+			},
+			"weight": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateIntBetween(0, 65535),
+			},
+			"port": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validateIntBetween(1, 65535),
+			},
+		},
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceDNSRecordSetCreate,
+		ReadContext:   resourceDNSRecordSetRead,
+		UpdateContext: resourceDNSRecordSetUpdate,
+		DeleteContext: resourceDNSRecordSetDelete,
+		CustomizeDiff: resourceDNSRecordSetCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDNSRecordSetState,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"host": {
+				Type:      schema.TypeString,
+				Required:  true,
+				ForceNew:  true,
+				StateFunc: normalizeDNSHostState,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateDNSRecordSetType,
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validateTTL,
+			},
+			"values": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateTXTUDPLength,
+				},
+				ConflictsWith: []string{"record"},
+			},
+			"record": {
+				Type:          schema.TypeSet,
+				Optional:      true,
+				ConflictsWith: []string{"values"},
+				Elem:          record,
+				// Only MX and SRV sets take record blocks, and their data
+				// normalizes alike.
+				Set: hashDNSRecordBlock(record, "MX"),
+			},
+		},
+	}
+}
+
+func validateDNSRecordSetType(v interface{}, key string) ([]string, []error) {
+	recordType := v.(string)
+	for _, known := range dnsRecordSetTypes {
+		if recordType == known {
+			return nil, nil
+		}
+	}
+
+	return nil, []error{fmt.Errorf("%s: %q is not one of %s", key, recordType, strings.Join(dnsRecordSetTypes, ", "))}
+}
+
+// resourceDNSRecordSetCustomizeDiff checks that MX and SRV sets use record
+// blocks, other sets use values, and that each value is valid for the type.
+func resourceDNSRecordSetCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("type") || !d.NewValueKnown("values") || !d.NewValueKnown("record") {
+		return nil
+	}
+	recordType := d.Get("type").(string)
+
+	if recordType == "MX" || recordType == "SRV" {
+		if d.Get("values").(*schema.Set).Len() > 0 {
+			return fmt.Errorf("values: %s record sets take record blocks", recordType)
+		}
+	} else if d.Get("record").(*schema.Set).Len() > 0 {
+		return fmt.Errorf("record: only MX and SRV record sets take record blocks, use values")
+	}
+
+	records := dnsRecordSetRecords(recordType, d.Get("values").(*schema.Set), d.Get("record").(*schema.Set))
+	if len(records) == 0 {
+		return fmt.Errorf("a record set needs at least one value or record block")
+	}
+
+	seen := map[string]string{}
+	for _, record := range records {
+		if err := validateDNSRecordData(recordType, record.Data); err != nil {
+			return err
+		}
+		if recordType != "MX" && recordType != "SRV" {
+			normalized := normalizeDNSRecordData(recordType, record.Data)
+			if other, ok := seen[normalized]; ok {
+				return fmt.Errorf("values: %q and %q are the same %s record", other, record.Data, recordType)
+			}
+			seen[normalized] = record.Data
+		}
+		if recordType == "SRV" && record.Port == 0 {
+			return fmt.Errorf("record: port is required for SRV records")
+		}
+	}
+
+	return nil
+}
+
+func resourceDNSRecordSetState(ctx context.Context, d *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+	domainId, host, recordType, err := parseDNSRecordSetId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	errs = append(errs, d.Set("domain_id", domainId))
+	errs = append(errs, d.Set("host", host))
+	errs = append(errs, d.Set("type", recordType))
+	for _, err = range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceDNSRecordSetCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	domainId := d.Get("domain_id").(int)
+	host := normalizeDNSHost(d.Get("host").(string))
+	recordType := d.Get("type").(string)
+
+	diags = syncDNSRecordSet(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(dnsRecordSetId(domainId, host, recordType))
+
+	// refresh state
+	diags = append(diags, resourceDNSRecordSetRead(ctx, d, m)...)
+
+	return diags
+}
+
+func resourceDNSRecordSetRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, host, recordType, err := parseDNSRecordSetId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, host, recordType)
+	if err != nil {
+		return readError(d, "DNS record set", err)
+	}
+	records = filterDNSRecords(records, host, recordType)
+
+	if len(records) == 0 && !d.IsNewResource() {
+		d.SetId("")
+		return nil
+	}
+
+	var errs []error
+	errs = append(errs, d.Set("domain_id", domainId))
+	errs = append(errs, d.Set("host", normalizeDNSHost(host)))
+	errs = append(errs, d.Set("type", recordType))
+	if len(records) > 0 {
+		errs = append(errs, d.Set("ttl", dnsRecordSetTTL(records)))
+	}
+
+	if recordType == "MX" || recordType == "SRV" {
+		blocks := make([]interface{}, 0, len(records))
+		for _, record := range records {
+			block := map[string]interface{}{
+				"data":     normalizeDNSRecordData(recordType, record.Data),
+				"priority": record.Priority,
+			}
+			if recordType == "SRV" {
+				block["weight"] = record.Weight
+				block["port"] = record.Port
+			}
+			blocks = append(blocks, block)
+		}
+		errs = append(errs, d.Set("record", blocks))
+	} else {
+		// A set compares its values as written, so values equivalent for
+		// the type to one already in the state keep the spelling there.
+		spelling := map[string]string{}
+		for _, value := range d.Get("values").(*schema.Set).List() {
+			spelling[normalizeDNSRecordData(recordType, value.(string))] = value.(string)
+		}
+
+		values := make([]interface{}, 0, len(records))
+		for _, record := range records {
+			value := normalizeDNSRecordData(recordType, record.Data)
+			if spelled, ok := spelling[value]; ok {
+				value = spelled
+			}
+			values = append(values, value)
+		}
+		errs = append(errs, d.Set("values", values))
+	}
+
+	for _, err = range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+// dnsRecordSetTTL returns the TTL the records of a set share, or 0 if they
+// differ, which no configuration has and so shows up as a change syncing
+// them.
+func dnsRecordSetTTL(records []model.DnsRecord) int {
+	for _, record := range records[1:] {
+		if record.Ttl != records[0].Ttl {
+			return 0
+		}
+	}
+	return records[0].Ttl
+}
+
+func resourceDNSRecordSetUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("ttl", "values", "record") {
+		if diags := syncDNSRecordSet(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceDNSRecordSetRead(ctx, d, m)
+}
+
+func resourceDNSRecordSetDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, host, recordType, err := parseDNSRecordSetId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, host, recordType)
+	if err != nil {
+		return deleteError("DNS record set", err)
+	}
+
+	for _, record := range filterDNSRecords(records, host, recordType) {
+		err = client.DeleteRecord(ctx, domainId, record.Id)
+		if err != nil {
+			diags = append(diags, deleteError("DNS record", err)...)
+		}
+	}
+
+	return diags
+}
+
+// syncDNSRecordSet makes the records of the host and type match the
+// configuration, changing only the records that differ.
+func syncDNSRecordSet(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+	host := normalizeDNSHost(d.Get("host").(string))
+	recordType := d.Get("type").(string)
+	ttl := d.Get("ttl").(int)
+
+	existing, err := client.ListRecords(ctx, domainId, host, recordType)
+	if err != nil {
+		return apiError("listing DNS records", err)
+	}
+	existing = filterDNSRecords(existing, host, recordType)
+
+	desired := dnsRecordSetRecords(recordType, d.Get("values").(*schema.Set), d.Get("record").(*schema.Set))
+	for i := range desired {
+		desired[i].Host = host
+		desired[i].Ttl = ttl
+	}
+
+	return applyDNSRecordChanges(ctx, client, domainId, existing, desired)
+}
+
+// applyDNSRecordChanges makes the records in existing match desired, changing
+// only the records that differ.
+func applyDNSRecordChanges(ctx context.Context, client client.API, domainId int, existing, desired []model.DnsRecord) diag.Diagnostics {
+	creates, updates, deletes := diffDNSRecords(existing, desired)
+	for _, records := range [][]model.DnsRecord{creates, updates} {
+		for i := range records {
+			if records[i].Type == "TXT" {
+				records[i].Data = encodeTXTData(records[i].Data)
+			}
+		}
+	}
+
+	// Delete first, so values moving between records never collide.
+	for _, recordId := range deletes {
+		err := client.DeleteRecord(ctx, domainId, recordId)
+		if err != nil {
+			if diags := deleteError("DNS record", err); diags.HasError() {
+				return diags
+			}
+		}
+	}
+	for i := range updates {
+		err := client.UpdateRecord(ctx, domainId, &updates[i])
+		if err != nil {
+			return apiError("updating DNS record", err)
+		}
+	}
+	for i := range creates {
+		_, err := client.CreateRecord(ctx, domainId, &creates[i])
+		if err != nil {
+			return apiError("creating DNS record", err)
+		}
+	}
+
+	return nil
+}
+
+// dnsRecordSetRecords builds the records a set configures, from values or
+// record blocks depending on recordType. Host and TTL are left unset.
+func dnsRecordSetRecords(recordType string, values, blocks *schema.Set) []model.DnsRecord {
+	var records []model.DnsRecord
+
+	for _, value := range values.List() {
+		records = append(records, model.DnsRecord{Type: recordType, Data: value.(string)})
+	}
+
+	for _, raw := range blocks.List() {
+		block := raw.(map[string]interface{})
+		record := model.DnsRecord{
+			Type:     recordType,
+			Data:     block["data"].(string),
+			Priority: block["priority"].(string),
+		}
+		if recordType == "SRV" {
+			record.Weight = block["weight"].(int)
+			record.Port = block["port"].(int)
+		}
+		records = append(records, record)
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return dnsRecordKey(records[i]) < dnsRecordKey(records[j])
+	})
+
+	return records
+}
+
+// diffDNSRecords works out the changes turning existing into desired.
+// Records with the same contents are kept, only updating the TTL if it
+// differs. Remaining existing records are reused for remaining desired
+// records of the same host and type before anything is created or deleted.
+func diffDNSRecords(existing, desired []model.DnsRecord) (creates, updates []model.DnsRecord, deletes []int) {
+	unmatched := map[string][]model.DnsRecord{}
+	var unmatchedKeys []string
+	for _, record := range existing {
+		key := dnsRecordKey(record)
+		if len(unmatched[key]) == 0 {
+			unmatchedKeys = append(unmatchedKeys, key)
+		}
+		unmatched[key] = append(unmatched[key], record)
+	}
+
+	var remaining []model.DnsRecord
+	for _, record := range desired {
+		key := dnsRecordKey(record)
+		if matches := unmatched[key]; len(matches) > 0 {
+			unmatched[key] = matches[1:]
+			if matches[0].Ttl != record.Ttl {
+				record.Id = matches[0].Id
+				updates = append(updates, record)
+			}
+			continue
+		}
+		remaining = append(remaining, record)
+	}
+
+	// The leftovers, grouped by host and type, in the order of existing.
+	leftover := map[string][]model.DnsRecord{}
+	var groups []string
+	for _, key := range unmatchedKeys {
+		for _, record := range unmatched[key] {
+			group := normalizeDNSHost(record.Host) + "\x00" + record.Type
+			if _, ok := leftover[group]; !ok {
+				groups = append(groups, group)
+			}
+			leftover[group] = append(leftover[group], record)
+		}
+	}
+
+	for _, record := range remaining {
+		group := normalizeDNSHost(record.Host) + "\x00" + record.Type
+		if reuse := leftover[group]; len(reuse) > 0 {
+			leftover[group] = reuse[1:]
+			record.Id = reuse[0].Id
+			updates = append(updates, record)
+			continue
+		}
+		creates = append(creates, record)
+	}
+
+	for _, group := range groups {
+		for _, record := range leftover[group] {
+			deletes = append(deletes, record.Id)
+		}
+	}
+
+	return creates, updates, deletes
+}
+
+// dnsRecordKey identifies a record by its host, type and contents, where
+// equivalent hosts and data give the same key.
+func dnsRecordKey(record model.DnsRecord) string {
+	return fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%d\x00%d\x00%s", normalizeDNSHost(record.Host), record.Type, normalizeDNSRecordData(record.Type, record.Data), record.Priority, record.Weight, record.Port,
+		formatIntPointers(record.Flags, record.Tag, record.Alg, record.Digest, record.Usage, record.Selector, record.Dtype))
+}
+
+func formatIntPointers(values ...*int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		if value != nil {
+			parts[i] = strconv.Itoa(*value)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// filterDNSRecords keeps the records that are exactly of host and recordType,
+// in case the API matches the host filter more loosely.
+func filterDNSRecords(records []model.DnsRecord, host, recordType string) []model.DnsRecord {
+	var filtered []model.DnsRecord
+	for _, record := range records {
+		if normalizeDNSHost(record.Host) == normalizeDNSHost(host) && record.Type == recordType {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// dnsRecordSetId builds the `domain_id/host/type` ID of a record set.
+func dnsRecordSetId(domainId int, host, recordType string) string {
+	return fmt.Sprintf("%d/%s/%s", domainId, host, recordType)
+}
+
+func parseDNSRecordSetId(id string) (int, string, string, error) {
+	parts := strings.Split(id, "/")
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return 0, "", "", fmt.Errorf("unexpected format of ID (%s), expected domain_id/host/type", id)
+	}
+
+	domainId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", "", fmt.Errorf("unexpected format of ID (%s), expected domain_id/host/type: %w", id, err)
+	}
+
+	return domainId, parts[1], parts[2], nil
+}
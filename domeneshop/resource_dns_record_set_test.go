@@ -0,0 +1,283 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"sort"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestAccDNSRecordSet_values(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	other := server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "AAAA", Data: "2001:db8::1", Ttl: 3600})
+
+	var ids []int
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record_set", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "www",
+				"type":      "A",
+				"values":    []interface{}{"192.0.2.1", "192.0.2.2", "192.0.2.3"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if state.ID != fmt.Sprintf("%d/www/A", domain.Id) {
+					return fmt.Errorf("unexpected id %s", state.ID)
+				}
+				ids = testAccRecordIds(server, domain.Id, "A")
+				if len(ids) != 3 {
+					return fmt.Errorf("expected 3 records, got %v", server.Records(domain.Id))
+				}
+				return nil
+			},
+		},
+		{
+			// Removing one value deletes only that record.
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "www",
+				"type":      "A",
+				"values":    []interface{}{"192.0.2.1", "192.0.2.3"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				remaining := testAccRecordIds(server, domain.Id, "A")
+				if len(remaining) != 2 || remaining[0] != ids[0] || remaining[1] != ids[2] {
+					return fmt.Errorf("expected records %d and %d to remain, got %v", ids[0], ids[2], server.Records(domain.Id))
+				}
+				return nil
+			},
+		},
+		{
+			// Changing the TTL updates the records in place.
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "www",
+				"type":      "A",
+				"ttl":       300,
+				"values":    []interface{}{"192.0.2.1", "192.0.2.3"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				for _, record := range server.Records(domain.Id) {
+					if record.Type == "A" && record.Ttl != 300 {
+						return fmt.Errorf("unexpected record %+v", record)
+					}
+				}
+				if remaining := testAccRecordIds(server, domain.Id, "A"); len(remaining) != 2 || remaining[0] != ids[0] {
+					return fmt.Errorf("records were replaced: %v", server.Records(domain.Id))
+				}
+				return nil
+			},
+		},
+		{
+			// A record whose TTL was changed elsewhere is brought back
+			// in line.
+			PreConfig: func() {
+				server.RemoveRecord(domain.Id, ids[2])
+				ids[2] = server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "A", Data: "192.0.2.3", Ttl: 600})
+			},
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "www",
+				"type":      "A",
+				"ttl":       300,
+				"values":    []interface{}{"192.0.2.1", "192.0.2.3"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				for _, record := range server.Records(domain.Id) {
+					if record.Type == "A" && record.Ttl != 300 {
+						return fmt.Errorf("unexpected record %+v", record)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+	}, func() error {
+		records := server.Records(domain.Id)
+		if len(records) != 1 || records[0].Id != other {
+			return fmt.Errorf("expected only the AAAA record to remain, got %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDNSRecordSet_mx(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record_set", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "@",
+				"type":      "MX",
+				"values":    []interface{}{"mx1.example.com"},
+			},
+			ExpectError: regexp.MustCompile("take record blocks"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "@",
+				"type":      "MX",
+				"record": []interface{}{
+					map[string]interface{}{"data": "mx1.example.com", "priority": "10"},
+					map[string]interface{}{"data": "mx2.example.com", "priority": "20"},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if records := server.Records(domain.Id); len(records) != 2 {
+					return fmt.Errorf("expected 2 records, got %+v", records)
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "@",
+				"type":      "MX",
+				"record": []interface{}{
+					map[string]interface{}{"data": "mx1.example.com", "priority": "10"},
+					map[string]interface{}{"data": "mx3.example.com", "priority": "30"},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 2 {
+					return fmt.Errorf("expected 2 records, got %+v", records)
+				}
+				for _, record := range records {
+					if record.Data == "mx2.example.com" {
+						return fmt.Errorf("mx2 was not removed: %+v", records)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+	}, nil)
+}
+
+func TestAccDNSRecordSet_normalized(t *testing.T) {
+	testAccPreCheck(t)
+
+	cases := map[string]map[string]interface{}{
+		"AAAA expanded": {"host": "www", "type": "AAAA", "values": []interface{}{"2001:0db8:0000:0000:0000:0000:0000:0001", "2001:DB8::2"}},
+		"CNAME dotted":  {"host": "www", "type": "CNAME", "values": []interface{}{"Target.Example.com."}},
+		"TXT quoted":    {"host": "@", "type": "TXT", "values": []interface{}{`"v=spf1 " "-all"`, "token"}},
+		"host upper":    {"host": "WWW.", "type": "A", "values": []interface{}{"192.0.2.1"}},
+		"MX dotted": {"host": "@", "type": "MX", "record": []interface{}{
+			map[string]interface{}{"data": "MX1.example.com.", "priority": "10"},
+		}},
+	}
+
+	for name, config := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := fake.NewServer()
+			defer server.Close()
+			domain := server.AddDomain("example.com")
+
+			config["domain_id"] = domain.Id
+
+			// The plan after apply must be empty, though the config and
+			// the API differ in form.
+			testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record_set", []testAccStep{
+				{
+					Config: config,
+				},
+			}, nil)
+		})
+	}
+}
+
+func TestAccDNSRecordSet_txtCase(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	// TXT values are compared as written, so values differing only in
+	// case are two records.
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record_set", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "@",
+				"type":      "TXT",
+				"values":    []interface{}{"Foo.Example", "foo.example"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if state.Attributes["values.#"] != "2" {
+					return fmt.Errorf("expected 2 values, got %v", state.Attributes)
+				}
+				if records := server.Records(domain.Id); len(records) != 2 {
+					return fmt.Errorf("expected 2 records, got %+v", records)
+				}
+				return nil
+			},
+		},
+	}, nil)
+}
+
+func TestAccDNSRecordSet_invalid(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"duplicate": {
+			map[string]interface{}{"host": "@", "type": "NS", "values": []interface{}{"ns1.example.net", "NS1.example.net."}},
+			"are the same NS record",
+		},
+		"port": {
+			map[string]interface{}{"host": "_sip._tcp", "type": "SRV", "record": []interface{}{
+				map[string]interface{}{"data": "sip.example.com", "priority": "10", "weight": 5, "port": 0},
+			}},
+			"must be between 1 and 65535, got 0",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["domain_id"] = domain.Id
+			testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record_set", []testAccStep{
+				{
+					Config:      tc.config,
+					ExpectError: regexp.MustCompile(regexp.QuoteMeta(tc.err)),
+				},
+			}, nil)
+		})
+	}
+}
+
+func testAccRecordIds(server *fake.Server, domainId int, recordType string) []int {
+	var ids []int
+	for _, record := range server.Records(domainId) {
+		if record.Type == recordType {
+			ids = append(ids, record.Id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
@@ -0,0 +1,407 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"testing"
+)
+
+func TestAccDNSRecord_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "A",
+				"host":      "www",
+				"data":      "192.0.2.1",
+				"ttl":       300,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Data != "192.0.2.1" || records[0].Ttl != 300 {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "A",
+				"host":      "www",
+				"data":      "192.0.2.2",
+				"ttl":       600,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Data != "192.0.2.2" || records[0].Ttl != 600 {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if state.ID != fmt.Sprintf("%d/%d", domain.Id, records[0].Id) {
+					return fmt.Errorf("unexpected id %s", state.ID)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState:             true,
+			ImportStateVerifyIgnore: []string{"last_updated"},
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("records still exist: %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDNSRecord_mx(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "MX",
+				"host":      "@",
+				"data":      "mx.example.com",
+				"ttl":       3600,
+			},
+			ExpectError: regexp.MustCompile("priority is required"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "MX",
+				"host":      "@",
+				"data":      "mx.example.com",
+				"ttl":       3600,
+				"priority":  "10",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if state.Attributes["priority"] != "10" {
+					return fmt.Errorf("expected priority 10, got %q", state.Attributes["priority"])
+				}
+				return nil
+			},
+		},
+	}, nil)
+}
+
+func TestAccDNSRecord_caa(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "CAA",
+				"ttl":       3600,
+				"host":      "@",
+				"tag":       "issue",
+				"value":     "letsencrypt.org",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Data != "letsencrypt.org" || records[0].Tag == nil || *records[0].Tag != 0 || records[0].Flags == nil || *records[0].Flags != 0 {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if state.Attributes["tag"] != "issue" || state.Attributes["value"] != "letsencrypt.org" {
+					return fmt.Errorf("unexpected state %v", state.Attributes)
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "CAA",
+				"ttl":       3600,
+				"host":      "@",
+				"flags":     128,
+				"tag":       "iodef",
+				"value":     "mailto:security@example.com",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || *records[0].Tag != 2 || *records[0].Flags != 128 {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState:             true,
+			ImportStateVerifyIgnore: []string{"last_updated"},
+		},
+	}, nil)
+}
+
+func TestAccDNSRecord_ds(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id":   domain.Id,
+				"type":        "DS",
+				"ttl":         3600,
+				"host":        "sub",
+				"key_tag":     2371,
+				"alg":         13,
+				"digest_type": 2,
+				"digest":      "1F987CC6583E92DF0890718C42A7AA5E5F0E2B3E4F0D3A5D1E5C9F1B2A3C4D5E",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || *records[0].Tag != 2371 || *records[0].Alg != 13 || *records[0].Digest != 2 {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+	}, nil)
+}
+
+func TestAccDNSRecord_tlsa(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "TLSA",
+				"ttl":       3600,
+				"host":      "_443._tcp.www",
+				"usage":     3,
+				"selector":  1,
+				"dtype":     1,
+				"data":      "d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || *records[0].Usage != 3 || *records[0].Selector != 1 || *records[0].Dtype != 1 {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+	}, nil)
+}
+
+func TestAccDNSRecord_normalized(t *testing.T) {
+	testAccPreCheck(t)
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		data   string
+	}{
+		"AAAA expanded": {map[string]interface{}{"type": "AAAA", "host": "WWW", "data": "2001:0DB8:0000::0001"}, "2001:db8::1"},
+		"CNAME dotted":  {map[string]interface{}{"type": "CNAME", "host": "www.", "data": "Target.Example.org."}, "target.example.org"},
+		"MX dotted":     {map[string]interface{}{"type": "MX", "host": "@", "data": "mx.example.com.", "priority": "10"}, "mx.example.com"},
+		"TXT quoted":    {map[string]interface{}{"type": "TXT", "host": "@", "data": `"v=spf1 " "-all"`}, "v=spf1 -all"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := fake.NewServer()
+			defer server.Close()
+			domain := server.AddDomain("example.com")
+
+			tc.config["domain_id"] = domain.Id
+
+			// The plan after apply must be empty, though the config and
+			// the API differ in form, and ttl is left to the API.
+			testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+				{
+					Config: tc.config,
+					Check: func(state *terraform.InstanceState) error {
+						records := server.Records(domain.Id)
+						if len(records) != 1 || records[0].Data != tc.data || records[0].Ttl != 3600 {
+							return fmt.Errorf("unexpected records %+v", records)
+						}
+						return nil
+					},
+				},
+			}, nil)
+		})
+	}
+}
+
+func TestAccDNSRecord_longTXT(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	dkim := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEA", 10)
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "TXT",
+				"host":      "selector._domainkey",
+				"data":      dkim,
+				"ttl":       3600,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || strings.Count(records[0].Data, `" "`) != 1 {
+					return fmt.Errorf("expected the data to be split into 2 strings, got %+v", records)
+				}
+				if state.Attributes["data"] != dkim {
+					return fmt.Errorf("expected the data to be joined back, got %q", state.Attributes["data"])
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "TXT",
+				"host":      "selector._domainkey",
+				"data":      strings.Repeat("a", 65300),
+				"ttl":       3600,
+			},
+			ExpectError: regexp.MustCompile("longer than the 65535 bytes"),
+		},
+	}, nil)
+}
+
+func TestAccDNSRecord_invalidTTL(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "TXT",
+				"host":      "@",
+				"data":      "hello",
+				"ttl":       90,
+			},
+			ExpectError: regexp.MustCompile("multiple of 60"),
+		},
+	}, nil)
+}
+
+func TestAccDNSRecord_invalidData(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "AAAA",
+				"host":      "www",
+				"data":      "192.0.2.1",
+			},
+			ExpectError: regexp.MustCompile("not a valid IPv6 address"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "CNAME",
+				"host":      "@",
+				"data":      "www.example.com",
+			},
+			ExpectError: regexp.MustCompile("CNAME record cannot be placed"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"type":      "CNAME",
+				"host":      "@.",
+				"data":      "www.example.com",
+			},
+			ExpectError: regexp.MustCompile("CNAME record cannot be placed"),
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("expected no records to be created, got %d", len(records))
+		}
+		return nil
+	})
+}
+
+func TestAccDNSRecord_disappears(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	config := map[string]interface{}{
+		"domain_id": domain.Id,
+		"type":      "CNAME",
+		"host":      "www",
+		"data":      "example.com",
+		"ttl":       3600,
+	}
+
+	var recordId int
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_record", []testAccStep{
+		{
+			Config: config,
+			Check: func(state *terraform.InstanceState) error {
+				recordId = server.Records(domain.Id)[0].Id
+				return nil
+			},
+		},
+		{
+			// deleted in the control panel, so the record is recreated
+			PreConfig: func() {
+				server.RemoveRecord(domain.Id, recordId)
+			},
+			Config: config,
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Id == recordId {
+					return fmt.Errorf("expected the record to be recreated, got %+v", records)
+				}
+				return nil
+			},
+		},
+	}, nil)
+}
@@ -0,0 +1,92 @@
+package domeneshop
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"terraform-provider-domeneshop/domeneshop/zonefile"
+)
+
+const (
+	// maxTXTRDataLength is the longest a record can be on the wire, counting
+	// the length byte in front of each character-string.
+	maxTXTRDataLength = 65535
+	// udpTXTRDataLength is about the longest a TXT record can be and still
+	// fit in a plain 512 byte DNS response over UDP, next to the question
+	// and the rest of the answer.
+	udpTXTRDataLength = 400
+)
+
+// encodeTXTData returns TXT data as sent to the API: as is when it fits in
+// one character-string, or else as quoted character-strings of at most 255
+// bytes. Data already written as quoted strings is joined and split again.
+func encodeTXTData(data string) string {
+	data = normalizeDNSRecordData("TXT", data)
+	strs := zonefile.SplitTXT(data)
+	if len(strs) == 1 {
+		return data
+	}
+
+	for i, str := range strs {
+		strs[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(str) + `"`
+	}
+	return strings.Join(strs, " ")
+}
+
+// joinTXTData returns records with the data of TXT records split into quoted
+// character-strings joined back together.
+func joinTXTData(records []model.DnsRecord) []model.DnsRecord {
+	joined := make([]model.DnsRecord, len(records))
+	for i, record := range records {
+		if record.Type == "TXT" {
+			record.Data = normalizeDNSRecordData("TXT", record.Data)
+		}
+		joined[i] = record
+	}
+	return joined
+}
+
+// txtRDataLength returns the length of TXT data on the wire.
+func txtRDataLength(data string) int {
+	data = normalizeDNSRecordData("TXT", data)
+	return len(data) + len(zonefile.SplitTXT(data))
+}
+
+func validateTXTLength(data string) error {
+	if length := txtRDataLength(data); length > maxTXTRDataLength {
+		return fmt.Errorf("data: TXT record of %d bytes is longer than the %d bytes a DNS record can hold", length, maxTXTRDataLength)
+	}
+	return nil
+}
+
+// validateTXTUDPLength warns at plan time about data too long for a plain DNS
+// response over UDP, which resolvers without EDNS see truncated. The type of
+// the record isn't known here, but only TXT data gets that long.
+func validateTXTUDPLength(v interface{}, key string) ([]string, []error) {
+	if length := txtRDataLength(v.(string)); length > udpTXTRDataLength {
+		return []string{fmt.Sprintf("%s: the TXT record is %d bytes, more than fits in a DNS response over UDP without EDNS. "+
+			"Resolvers without EDNS get a truncated response and must retry over TCP, which some don't", key, length)}, nil
+	}
+	return nil, nil
+}
+
+// dnsRecordTXTWarnings warns about TXT records too long for a plain DNS
+// response over UDP, for records whose data isn't known until apply.
+func dnsRecordTXTWarnings(records ...model.DnsRecord) diag.Diagnostics {
+	var diags diag.Diagnostics
+	for _, record := range records {
+		if record.Type != "TXT" {
+			continue
+		}
+		if length := txtRDataLength(record.Data); length > udpTXTRDataLength {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Long TXT record for %s", record.Host),
+				Detail: fmt.Sprintf("The TXT record is %d bytes, more than fits in a DNS response over UDP without EDNS. "+
+					"Resolvers without EDNS get a truncated response and must retry over TCP, which some don't.", length),
+			})
+		}
+	}
+	return diags
+}
@@ -0,0 +1,98 @@
+package domeneshop
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"terraform-provider-domeneshop/domeneshop/zonefile"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEncodeTXTData(t *testing.T) {
+	long := "v=DKIM1; k=rsa; p=" + strings.Repeat("MIIBIjANBgkqhkiG9w0B", 20)
+
+	cases := []struct {
+		data    string
+		strings int
+	}{
+		{"v=spf1 -all", 1},
+		{strings.Repeat("a", 255), 1},
+		{strings.Repeat("a", 256), 2},
+		{long, 2},
+		{`"` + long[:200] + `" "` + long[200:] + `"`, 2},
+		{strings.Repeat(`"\`, 200), 2},
+		{strings.Repeat("æøå", 100), 3},
+	}
+
+	for _, tc := range cases {
+		encoded := encodeTXTData(tc.data)
+		joined := normalizeDNSRecordData("TXT", tc.data)
+
+		if tc.strings == 1 {
+			if encoded != joined {
+				t.Errorf("expected %q to be sent as is, got %q", tc.data, encoded)
+			}
+			continue
+		}
+
+		decoded, ok := unquoteTXT(encoded)
+		if !ok || decoded != joined {
+			t.Errorf("%q doesn't join back to the data: %q", encoded, decoded)
+		}
+		if encodeTXTData(encoded) != encoded {
+			t.Errorf("encoding %q again changed it", encoded)
+		}
+
+		strs := zonefile.SplitTXT(joined)
+		if len(strs) != tc.strings {
+			t.Errorf("expected %d strings, got %d", tc.strings, len(strs))
+		}
+		for _, str := range strs {
+			if len(str) > zonefile.MaxStringLength || !utf8.ValidString(str) {
+				t.Errorf("invalid string %q of %d bytes", str, len(str))
+			}
+		}
+	}
+}
+
+func TestValidateTXTLength(t *testing.T) {
+	if err := validateDNSRecordData("TXT", strings.Repeat("a", 60000)); err != nil {
+		t.Errorf("expected 60000 bytes to be valid, got %v", err)
+	}
+	if err := validateDNSRecordData("TXT", strings.Repeat("a", 65300)); err == nil {
+		t.Errorf("expected 65300 bytes to be too long, counting the length bytes")
+	}
+}
+
+func TestValidateTXTUDPLength(t *testing.T) {
+	if warnings, errs := validateTXTUDPLength("v=spf1 -all", "data"); len(warnings) != 0 || len(errs) != 0 {
+		t.Errorf("expected no warnings for a short record, got %v %v", warnings, errs)
+	}
+	if warnings, errs := validateTXTUDPLength(strings.Repeat("a", 600), "data"); len(warnings) != 1 || len(errs) != 0 {
+		t.Errorf("expected a warning for a long record, got %v %v", warnings, errs)
+	}
+
+	// The warning comes from validating the configuration, before apply.
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"domain_id": 1,
+		"host":      "@",
+		"type":      "TXT",
+		"values":    []interface{}{strings.Repeat("a", 600)},
+	})
+	if diags := resourceDNSRecordSet().Validate(config); len(diags) != 1 || diags.HasError() {
+		t.Errorf("expected a warning for a long value in a record set, got %+v", diags)
+	}
+}
+
+func TestDNSRecordTXTWarnings(t *testing.T) {
+	diags := dnsRecordTXTWarnings(
+		model.DnsRecord{Host: "short", Type: "TXT", Data: "v=spf1 -all"},
+		model.DnsRecord{Host: "long", Type: "TXT", Data: strings.Repeat("a", 600)},
+		model.DnsRecord{Host: "tlsa", Type: "TLSA", Data: strings.Repeat("a", 600)},
+	)
+
+	if len(diags) != 1 || diags.HasError() || !strings.Contains(diags[0].Summary, "long") {
+		t.Errorf("expected a warning about the long record, got %+v", diags)
+	}
+}
@@ -0,0 +1,254 @@
+package domeneshop
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+var dnsRecordTypes = []string{"A", "AAAA", "ANAME", "CAA", "CNAME", "DS", "MX", "NS", "SRV", "TLSA", "TXT"}
+
+func validateDNSRecordType(v interface{}, key string) ([]string, []error) {
+	recordType := v.(string)
+	for _, known := range dnsRecordTypes {
+		if recordType == known {
+			return nil, nil
+		}
+	}
+
+	return nil, []error{fmt.Errorf("%s: %q is not one of %s", key, recordType, strings.Join(dnsRecordTypes, ", "))}
+}
+
+func validateTTL(v interface{}, key string) ([]string, []error) {
+	if err := model.CheckTTL(v.(int)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", key, err)}
+	}
+
+	return nil, nil
+}
+
+// dnsRecordTypeFields lists the fields, besides data, each record type takes.
+var dnsRecordTypeFields = map[string][]string{
+	"MX":   {"priority"},
+	"SRV":  {"priority", "weight", "port"},
+	"CAA":  {"flags", "tag", "value"},
+	"DS":   {"key_tag", "alg", "digest_type", "digest"},
+	"TLSA": {"usage", "selector", "dtype"},
+}
+
+// dnsRecordRequiredFields lists the fields each record type can't do without.
+// The remaining fields of the type default to 0.
+var dnsRecordRequiredFields = map[string][]string{
+	"MX":  {"priority"},
+	"SRV": {"priority", "port"},
+	"CAA": {"tag", "value"},
+	"DS":  {"alg", "digest_type", "digest"},
+}
+
+// resourceDNSRecordCustomizeDiff validates the combination of type, host,
+// data and the type specific fields at plan time. Values not known until
+// apply are skipped.
+func resourceDNSRecordCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("type") {
+		return nil
+	}
+	recordType := d.Get("type").(string)
+
+	set := map[string]bool{}
+	for _, field := range append([]string{"data"}, dnsRecordFields()...) {
+		if !d.NewValueKnown(field) {
+			set[field] = true
+			continue
+		}
+		_, set[field] = d.GetOk(field)
+	}
+
+	if err := validateDNSRecordFields(recordType, set); err != nil {
+		return err
+	}
+
+	if d.NewValueKnown("data") && dnsRecordTypeHasData(recordType) {
+		if err := validateDNSRecordData(recordType, d.Get("data").(string)); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown("host") && recordType == "CNAME" && normalizeDNSHost(d.Get("host").(string)) == "@" {
+		return fmt.Errorf("host: a CNAME record cannot be placed at the domain itself (@), use an ANAME or A record")
+	}
+
+	return nil
+}
+
+// dnsRecordTypeHasData reports whether records of recordType keep their
+// contents in data. CAA and DS records use value and digest instead.
+func dnsRecordTypeHasData(recordType string) bool {
+	return recordType != "CAA" && recordType != "DS"
+}
+
+// dnsRecordFields returns all type specific fields, in a stable order.
+func dnsRecordFields() []string {
+	var fields []string
+	for _, recordType := range dnsRecordTypes {
+		fields = append(fields, dnsRecordTypeFields[recordType]...)
+	}
+	return fields
+}
+
+// validateDNSRecordData checks that data is valid for a record of recordType.
+func validateDNSRecordData(recordType, data string) error {
+	if data == "" {
+		return fmt.Errorf("data must not be empty")
+	}
+
+	switch recordType {
+	case "A":
+		if ip := net.ParseIP(data); ip == nil || ip.To4() == nil || strings.Contains(data, ":") {
+			return fmt.Errorf("data: %q is not a valid IPv4 address for an A record", data)
+		}
+	case "AAAA":
+		if ip := net.ParseIP(data); ip == nil || !strings.Contains(data, ":") {
+			return fmt.Errorf("data: %q is not a valid IPv6 address for an AAAA record", data)
+		}
+	case "ANAME", "CNAME", "MX", "NS", "SRV":
+		if !isHostname(data) {
+			return fmt.Errorf("data: %q is not a valid hostname for a %s record", data, recordType)
+		}
+	case "TLSA":
+		if !isHex(data) {
+			return fmt.Errorf("data: %q is not hex encoded certificate association data", data)
+		}
+	case "TXT":
+		return validateTXTLength(data)
+	}
+
+	return nil
+}
+
+// validateDNSRecordFields checks that the fields recordType needs are set, and
+// that no fields of other record types are. set tells which fields are set.
+func validateDNSRecordFields(recordType string, set map[string]bool) error {
+	if !dnsRecordTypeHasData(recordType) && set["data"] {
+		return fmt.Errorf("data is not used for %s records, use %s", recordType, strings.Join(dnsRecordTypeFields[recordType], ", "))
+	}
+	if dnsRecordTypeHasData(recordType) && !set["data"] {
+		return fmt.Errorf("data is required for %s records", recordType)
+	}
+
+	for _, field := range dnsRecordRequiredFields[recordType] {
+		if !set[field] {
+			return fmt.Errorf("%s is required for %s records", field, recordType)
+		}
+	}
+
+	allowed := map[string]bool{}
+	for _, field := range dnsRecordTypeFields[recordType] {
+		allowed[field] = true
+	}
+	for _, field := range dnsRecordFields() {
+		if set[field] && !allowed[field] {
+			return fmt.Errorf("%s is only allowed for %s records", field, strings.Join(dnsRecordTypesWithField(field), " and "))
+		}
+	}
+
+	return nil
+}
+
+func dnsRecordTypesWithField(field string) []string {
+	var types []string
+	for _, recordType := range dnsRecordTypes {
+		for _, f := range dnsRecordTypeFields[recordType] {
+			if f == field {
+				types = append(types, recordType)
+			}
+		}
+	}
+	return types
+}
+
+func validateIntBetween(min, max int) schema.SchemaValidateFunc {
+	return func(v interface{}, key string) ([]string, []error) {
+		if value := v.(int); value < min || value > max {
+			return nil, []error{fmt.Errorf("%s: must be between %d and %d, got %d", key, min, max, value)}
+		}
+		return nil, nil
+	}
+}
+
+func validateOneOf(values ...string) schema.SchemaValidateFunc {
+	return func(v interface{}, key string) ([]string, []error) {
+		if value := v.(string); !stringInSlice(value, values) {
+			return nil, []error{fmt.Errorf("%s: %q is not one of %s", key, value, strings.Join(values, ", "))}
+		}
+		return nil, nil
+	}
+}
+
+func validateNumericString(min, max int) schema.SchemaValidateFunc {
+	return func(v interface{}, key string) ([]string, []error) {
+		if value, err := strconv.Atoi(v.(string)); err != nil || value < min || value > max {
+			return nil, []error{fmt.Errorf("%s: must be a number between %d and %d, got %q", key, min, max, v)}
+		}
+		return nil, nil
+	}
+}
+
+func validateCAATag(v interface{}, key string) ([]string, []error) {
+	if _, err := caaTagValue(v.(string)); err != nil {
+		return nil, []error{fmt.Errorf("%s: %w", key, err)}
+	}
+	return nil, nil
+}
+
+func validateHex(v interface{}, key string) ([]string, []error) {
+	if !isHex(v.(string)) {
+		return nil, []error{fmt.Errorf("%s: %q is not hex encoded", key, v)}
+	}
+	return nil, nil
+}
+
+func isHex(value string) bool {
+	if value == "" || len(value)%2 != 0 {
+		return false
+	}
+	_, err := hex.DecodeString(value)
+	return err == nil
+}
+
+// isHostname reports whether name is a valid DNS hostname, with or without a
+// trailing dot. Underscores are allowed, as used in e.g. SRV targets.
+func isHostname(name string) bool {
+	name = strings.TrimSuffix(name, ".")
+	if name == "" || len(name) > 253 {
+		return false
+	}
+
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, r := range label {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+			default:
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func stringInSlice(value string, values []string) bool {
+	for _, other := range values {
+		if value == other {
+			return true
+		}
+	}
+	return false
+}
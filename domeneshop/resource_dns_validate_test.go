@@ -0,0 +1,97 @@
+package domeneshop
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDNSRecordData(t *testing.T) {
+	cases := []struct {
+		recordType string
+		data       string
+		valid      bool
+	}{
+		{"A", "192.0.2.1", true},
+		{"A", "2001:db8::1", false},
+		{"A", "::ffff:192.0.2.1", false},
+		{"A", "example.com", false},
+		{"AAAA", "2001:db8::1", true},
+		{"AAAA", "192.0.2.1", false},
+		{"CNAME", "www.example.com", true},
+		{"CNAME", "www.example.com.", true},
+		{"CNAME", "not a hostname", false},
+		{"CNAME", "-bad.example.com", false},
+		{"MX", "mx.example.com", true},
+		{"MX", "192.0.2.1:25", false},
+		{"SRV", "_sip.example.com", true},
+		{"TXT", "v=spf1 -all", true},
+		{"TXT", "", false},
+		{"NS", "ns1.hyp.net", true},
+		{"NS", "192.0.2.1/24", false},
+		{"ANAME", "example.herokudns.com", true},
+		{"TLSA", "d2abde240d7cd3ee6b4b28c54df034b97983a1d16e8a410e4561cb106618e971", true},
+		{"TLSA", "not hex", false},
+	}
+
+	for _, tc := range cases {
+		err := validateDNSRecordData(tc.recordType, tc.data)
+		if tc.valid && err != nil {
+			t.Errorf("%s %q: unexpected error: %v", tc.recordType, tc.data, err)
+		}
+		if !tc.valid && err == nil {
+			t.Errorf("%s %q: expected an error", tc.recordType, tc.data)
+		}
+	}
+}
+
+func TestValidateDNSRecordFields(t *testing.T) {
+	cases := map[string]struct {
+		recordType string
+		set        []string
+		err        string
+	}{
+		"MX":              {recordType: "MX", set: []string{"data", "priority"}},
+		"MX no priority":  {recordType: "MX", set: []string{"data"}, err: "priority is required"},
+		"MX with port":    {recordType: "MX", set: []string{"data", "priority", "port"}, err: "port is only allowed for SRV records"},
+		"SRV":             {recordType: "SRV", set: []string{"data", "priority", "port"}},
+		"SRV no port":     {recordType: "SRV", set: []string{"data", "priority"}, err: "port is required"},
+		"A with priority": {recordType: "A", set: []string{"data", "priority"}, err: "priority is only allowed for MX and SRV records"},
+		"TXT":             {recordType: "TXT", set: []string{"data"}},
+		"TXT no data":     {recordType: "TXT", err: "data is required"},
+		"CAA":             {recordType: "CAA", set: []string{"tag", "value"}},
+		"CAA with data":   {recordType: "CAA", set: []string{"data", "tag", "value"}, err: "data is not used for CAA records"},
+		"CAA no value":    {recordType: "CAA", set: []string{"tag"}, err: "value is required"},
+		"DS":              {recordType: "DS", set: []string{"key_tag", "alg", "digest_type", "digest"}},
+		"DS no digest":    {recordType: "DS", set: []string{"key_tag", "alg", "digest_type"}, err: "digest is required"},
+		"TLSA":            {recordType: "TLSA", set: []string{"data", "usage", "selector", "dtype"}},
+		"NS with tag":     {recordType: "NS", set: []string{"data", "tag"}, err: "tag is only allowed for CAA records"},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			set := map[string]bool{}
+			for _, field := range tc.set {
+				set[field] = true
+			}
+
+			err := validateDNSRecordFields(tc.recordType, set)
+			if tc.err == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected an error containing %q, got %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestValidateTTL(t *testing.T) {
+	for ttl, valid := range map[int]bool{60: true, 3600: true, 604800: true, 0: false, 30: false, 90: false, 604860: false} {
+		if _, errs := validateTTL(ttl, "ttl"); (len(errs) == 0) != valid {
+			t.Errorf("ttl %d: expected valid %v, got %v", ttl, valid, errs)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"path"
+	"strconv"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+func resourceDNSZone() *schema.Resource {
+	record := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"host": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateDNSRecordType,
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validateTTL,
+			},
+		},
+	}
+	for key, field := range dnsRecordFieldsSchema() {
+		record.Schema[key] = field
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceDNSZoneCreate,
+		ReadContext:   resourceDNSZoneRead,
+		UpdateContext: resourceDNSZoneUpdate,
+		DeleteContext: resourceDNSZoneDelete,
+		CustomizeDiff: resourceDNSZoneCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDNSZoneState,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"record": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     record,
+				Set:      hashDNSRecordBlock(record, ""),
+			},
+			"ignore": dnsZoneIgnoreSchema(),
+		},
+	}
+}
+
+// dnsZoneIgnoreSchema is the schema of the ignore blocks of resources that
+// manage every record of a domain.
+func dnsZoneIgnoreSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"host": {
+					Type:         schema.TypeString,
+					Optional:     true,
+					ValidateFunc: validateHostPattern,
+				},
+				"type": {
+					Type:     schema.TypeString,
+					Optional: true,
+				},
+			},
+		},
+	}
+}
+
+func validateHostPattern(v interface{}, key string) ([]string, []error) {
+	if _, err := path.Match(v.(string), ""); err != nil {
+		return nil, []error{fmt.Errorf("%s: invalid pattern %q: %w", key, v, err)}
+	}
+	return nil, nil
+}
+
+// resourceDNSZoneCustomizeDiff validates each record block like
+// domeneshop_dns_record validates its fields.
+func resourceDNSZoneCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if !d.NewValueKnown("record") {
+		return nil
+	}
+
+	for _, raw := range d.Get("record").(*schema.Set).List() {
+		block := raw.(map[string]interface{})
+		recordType := block["type"].(string)
+		host := block["host"].(string)
+
+		set := map[string]bool{}
+		for key, value := range block {
+			switch value := value.(type) {
+			case string:
+				set[key] = value != ""
+			case int:
+				set[key] = value != 0
+			}
+		}
+
+		if err := validateDNSRecordFields(recordType, set); err != nil {
+			return fmt.Errorf("record %s %s: %w", host, recordType, err)
+		}
+		if dnsRecordTypeHasData(recordType) {
+			if err := validateDNSRecordData(recordType, block["data"].(string)); err != nil {
+				return fmt.Errorf("record %s %s: %w", host, recordType, err)
+			}
+		}
+		if recordType == "CNAME" && normalizeDNSHost(host) == "@" {
+			return fmt.Errorf("record %s %s: a CNAME record cannot be placed at the domain itself (@), use an ANAME or A record", host, recordType)
+		}
+		if recordType == "NS" && normalizeDNSHost(host) == "@" {
+			return fmt.Errorf("record %s %s: the NS records of the domain itself are managed by Domeneshop", host, recordType)
+		}
+	}
+
+	return nil
+}
+
+func resourceDNSZoneState(ctx context.Context, d *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+	domainId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("unexpected format of ID (%s), expected domain_id", d.Id())
+	}
+
+	err = d.Set("domain_id", domainId)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceDNSZoneCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	domainId := d.Get("domain_id").(int)
+
+	diags = syncDNSZone(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(strconv.Itoa(domainId))
+
+	// refresh state
+	diags = append(diags, resourceDNSZoneRead(ctx, d, m)...)
+
+	return diags
+}
+
+func resourceDNSZoneRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return readError(d, "DNS zone", err)
+	}
+
+	blocks := make([]interface{}, 0, len(records))
+	for _, record := range dnsZoneRecords(records, d.Get("ignore").([]interface{})) {
+		block := flattenDNSRecordFields(&record)
+		block["host"] = normalizeDNSHost(record.Host)
+		block["type"] = record.Type
+		block["ttl"] = record.Ttl
+		blocks = append(blocks, block)
+	}
+
+	var errs []error
+	errs = append(errs, d.Set("domain_id", domainId))
+	errs = append(errs, d.Set("record", blocks))
+
+	for _, err = range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceDNSZoneUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("record", "ignore") {
+		if diags := syncDNSZone(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceDNSZoneRead(ctx, d, m)
+}
+
+func resourceDNSZoneDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return deleteError("DNS zone", err)
+	}
+
+	for _, record := range dnsZoneRecords(records, d.Get("ignore").([]interface{})) {
+		err = client.DeleteRecord(ctx, domainId, record.Id)
+		if err != nil {
+			diags = append(diags, deleteError("DNS record", err)...)
+		}
+	}
+
+	return diags
+}
+
+// syncDNSZone makes the records of the domain, except the ignored ones, match
+// the record blocks exactly.
+func syncDNSZone(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+	ignore := d.Get("ignore").([]interface{})
+
+	existing, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return apiError("listing DNS records", err)
+	}
+	existing = dnsZoneRecords(existing, ignore)
+
+	var desired []model.DnsRecord
+	for _, raw := range d.Get("record").(*schema.Set).List() {
+		block := raw.(map[string]interface{})
+		record, err := dnsRecordFromFields(func(key string) interface{} {
+			return block[key]
+		})
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if dnsRecordIgnored(*record, ignore) {
+			return diag.Errorf("record %s %s is matched by an ignore block", record.Host, record.Type)
+		}
+		desired = append(desired, *record)
+	}
+
+	return applyDNSRecordChanges(ctx, client, domainId, existing, desired)
+}
+
+// dnsZoneRecords returns the records not ignored, neither by the ignore
+// blocks nor for being NS records of the domain itself, which Domeneshop
+// manages.
+func dnsZoneRecords(records []model.DnsRecord, ignore []interface{}) []model.DnsRecord {
+	var managed []model.DnsRecord
+	for _, record := range records {
+		if !dnsRecordIgnored(record, ignore) && !isApexNS(record) {
+			managed = append(managed, record)
+		}
+	}
+	return managed
+}
+
+func isApexNS(record model.DnsRecord) bool {
+	return record.Type == "NS" && normalizeDNSHost(record.Host) == "@"
+}
+
+// dnsRecordIgnored reports whether record matches one of the ignore blocks.
+// A block matches when both its host pattern and type match, where an empty
+// host or type matches anything.
+func dnsRecordIgnored(record model.DnsRecord, ignore []interface{}) bool {
+	for _, raw := range ignore {
+		block, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		host := block["host"].(string)
+		recordType := block["type"].(string)
+		if host == "" && recordType == "" {
+			continue
+		}
+
+		// Hosts are matched normalized, so the apex matches "@" however
+		// the API or the configuration writes it.
+		if host != "" {
+			if matched, _ := path.Match(normalizeDNSHost(host), normalizeDNSHost(record.Host)); !matched {
+				continue
+			}
+		}
+		if recordType != "" && recordType != record.Type {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,245 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestAccDNSZone_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	kept := server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "A", Data: "192.0.2.1", Ttl: 3600})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "old", Type: "A", Data: "192.0.2.9", Ttl: 3600})
+	acme := server.AddRecord(domain.Id, model.DnsRecord{Host: "_acme-challenge.www", Type: "TXT", Data: "token", Ttl: 60})
+
+	ignore := []interface{}{
+		map[string]interface{}{"host": "_acme-challenge*", "type": "TXT"},
+	}
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_zone", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"ignore":    ignore,
+				"record": []interface{}{
+					map[string]interface{}{"host": "www", "type": "A", "data": "192.0.2.1"},
+					map[string]interface{}{"host": "@", "type": "MX", "data": "mx.example.com", "priority": "10"},
+					map[string]interface{}{"host": "@", "type": "CAA", "tag": "issue", "value": "letsencrypt.org"},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 4 {
+					return fmt.Errorf("expected 4 records, got %+v", records)
+				}
+				found := map[int]bool{}
+				for _, record := range records {
+					found[record.Id] = true
+					if record.Host == "old" {
+						return fmt.Errorf("undeclared record was not deleted: %+v", record)
+					}
+				}
+				if !found[kept] || !found[acme] {
+					return fmt.Errorf("expected records %d and %d to be kept, got %+v", kept, acme, records)
+				}
+				return nil
+			},
+		},
+		{
+			// Records changed behind Terraform's back are put back.
+			PreConfig: func() {
+				server.AddRecord(domain.Id, model.DnsRecord{Host: "manual", Type: "CNAME", Data: "example.org", Ttl: 3600})
+			},
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"ignore":    ignore,
+				"record": []interface{}{
+					map[string]interface{}{"host": "www", "type": "A", "data": "192.0.2.1", "ttl": 300},
+					map[string]interface{}{"host": "@", "type": "MX", "data": "mx.example.com", "priority": "10"},
+					map[string]interface{}{"host": "@", "type": "CAA", "tag": "issue", "value": "letsencrypt.org"},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 4 {
+					return fmt.Errorf("expected 4 records, got %+v", records)
+				}
+				for _, record := range records {
+					if record.Host == "manual" {
+						return fmt.Errorf("undeclared record was not deleted: %+v", record)
+					}
+					if record.Id == kept && record.Ttl != 300 {
+						return fmt.Errorf("record was not updated in place: %+v", record)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			// Without the ignore blocks, the imported zone also holds the
+			// ACME record.
+			ImportState:             true,
+			ImportStateVerifyIgnore: []string{"ignore", "record"},
+		},
+	}, func() error {
+		records := server.Records(domain.Id)
+		if len(records) != 1 || records[0].Id != acme {
+			return fmt.Errorf("expected only the ignored record to remain, got %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDNSZone_ignoreApex(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	verification := server.AddRecord(domain.Id, model.DnsRecord{Host: "", Type: "TXT", Data: "site-verification=abc", Ttl: 3600})
+
+	ignore := []interface{}{
+		map[string]interface{}{"host": "@", "type": "TXT"},
+	}
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_zone", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"ignore":    ignore,
+				"record": []interface{}{
+					map[string]interface{}{"host": "www", "type": "A", "data": "192.0.2.1"},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 2 || records[0].Id != verification {
+					return fmt.Errorf("expected record %d to be kept, got %+v", verification, records)
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"ignore":    ignore,
+				"record": []interface{}{
+					map[string]interface{}{"host": "www", "type": "A", "data": "192.0.2.1"},
+					map[string]interface{}{"host": "@.", "type": "TXT", "data": "v=spf1 -all"},
+				},
+			},
+			ExpectError: regexp.MustCompile("record @ TXT is matched by an ignore block"),
+		},
+	}, nil)
+}
+
+func TestAccDNSZone_normalized(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	// The plan after apply must be empty, though the config and the API
+	// differ in form.
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_zone", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"record": []interface{}{
+					map[string]interface{}{"host": "WWW", "type": "A", "data": "192.0.2.1"},
+					map[string]interface{}{"host": "www", "type": "AAAA", "data": "2001:0db8:0000:0000:0000:0000:0000:0001"},
+					map[string]interface{}{"host": "alias", "type": "CNAME", "data": "target.example.com."},
+					map[string]interface{}{"host": "@", "type": "TXT", "data": `"v=spf1 " "-all"`},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if records := server.Records(domain.Id); len(records) != 4 {
+					return fmt.Errorf("expected 4 records, got %+v", records)
+				}
+				return nil
+			},
+		},
+	}, nil)
+}
+
+func TestAccDNSZone_apex(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	ns := server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "NS", Data: "ns1.hyp.net", Ttl: 3600})
+	www := server.AddRecord(domain.Id, model.DnsRecord{Host: "WWW", Type: "CNAME", Data: "Target.Example.com.", Ttl: 3600})
+
+	// The NS records of the domain are left alone, and records stored in
+	// another form than declared are kept as they are.
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_zone", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"record": []interface{}{
+					map[string]interface{}{"host": "www", "type": "CNAME", "data": "target.example.com"},
+				},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 2 || records[0].Id != ns || records[1].Id != www {
+					return fmt.Errorf("expected records %d and %d to be kept, got %+v", ns, www, records)
+				}
+				return nil
+			},
+		},
+	}, func() error {
+		records := server.Records(domain.Id)
+		if len(records) != 1 || records[0].Id != ns {
+			return fmt.Errorf("expected only the NS record to remain, got %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDNSZone_invalid(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dns_zone", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"record": []interface{}{
+					map[string]interface{}{"host": "@", "type": "MX", "data": "mx.example.com"},
+				},
+			},
+			ExpectError: regexp.MustCompile("record @ MX: priority is required"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"record": []interface{}{
+					map[string]interface{}{"host": "@", "type": "NS", "data": "ns1.example.net"},
+				},
+			},
+			ExpectError: regexp.MustCompile("record @ NS: the NS records of the domain itself are managed by Domeneshop"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"record": []interface{}{
+					map[string]interface{}{"host": "@.", "type": "CNAME", "data": "www.example.com"},
+				},
+			},
+			ExpectError: regexp.MustCompile("record @. CNAME: a CNAME record cannot be placed at the domain itself"),
+		},
+	}, nil)
+}
@@ -0,0 +1,199 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"log"
+	"net"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+func resourceDynamicDNS() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDynamicDNSCreate,
+		ReadContext:   resourceDynamicDNSRead,
+		UpdateContext: resourceDynamicDNSUpdate,
+		DeleteContext: resourceDynamicDNSDelete,
+		Schema: map[string]*schema.Schema{
+			"hostname": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"myip": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"record_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceDynamicDNSCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	hostname := d.Get("hostname").(string)
+
+	err := client.DynDNSUpdate(ctx, hostname, d.Get("myip").(string))
+	if err != nil {
+		return apiError("updating dynamic DNS", err)
+	}
+
+	d.SetId(hostname)
+
+	return resourceDynamicDNSRead(ctx, d, m)
+}
+
+func resourceDynamicDNSRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	hostname := d.Id()
+
+	domains, err := client.ListDomains(ctx, "")
+	if err != nil {
+		return apiError("reading domains", err)
+	}
+
+	domain, host, err := domainForHostname(domains, hostname)
+	if err != nil {
+		if d.IsNewResource() {
+			return diag.FromErr(err)
+		}
+		log.Printf("[WARN] %v, removing %s from state", err, hostname)
+		d.SetId("")
+		return nil
+	}
+
+	records, err := client.ListRecords(ctx, domain.Id, host, "")
+	if err != nil {
+		return readError(d, "dynamic DNS record", err)
+	}
+
+	record := dynDNSRecord(records, d.Get("type").(string), d.Get("myip").(string))
+	if record == nil {
+		if d.IsNewResource() {
+			return diag.Errorf("no A or AAAA record found for %s after dynamic DNS update", hostname)
+		}
+		log.Printf("[WARN] no A or AAAA record found for %s, removing from state", hostname)
+		d.SetId("")
+		return nil
+	}
+
+	var errs []error
+	errs = append(errs, d.Set("hostname", hostname))
+	errs = append(errs, d.Set("myip", record.Data))
+	errs = append(errs, d.Set("domain_id", domain.Id))
+	errs = append(errs, d.Set("record_id", record.Id))
+	errs = append(errs, d.Set("type", record.Type))
+
+	for _, err = range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceDynamicDNSUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	if d.HasChange("myip") {
+		err := client.DynDNSUpdate(ctx, d.Id(), d.Get("myip").(string))
+		if err != nil {
+			return apiError("updating dynamic DNS", err)
+		}
+	}
+
+	return resourceDynamicDNSRead(ctx, d, m)
+}
+
+func resourceDynamicDNSDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	// The DDNS API cannot remove records, so the record it created is
+	// deleted through the DNS API instead.
+	err := client.DeleteRecord(ctx, d.Get("domain_id").(int), d.Get("record_id").(int))
+	if err != nil {
+		return deleteError("dynamic DNS record", err)
+	}
+
+	return diags
+}
+
+// domainForHostname finds the domain on the account that hostname belongs
+// to, and returns it along with the host part relative to that domain.
+func domainForHostname(domains []model.Domain, hostname string) (*model.Domain, string, error) {
+	hostname = strings.TrimSuffix(strings.ToLower(hostname), ".")
+
+	var match *model.Domain
+	for i := range domains {
+		name := strings.ToLower(domains[i].Domain)
+		if hostname != name && !strings.HasSuffix(hostname, "."+name) {
+			continue
+		}
+		// prefer the most specific domain, i.e. sub.example.com over example.com
+		if match == nil || len(name) > len(match.Domain) {
+			match = &domains[i]
+		}
+	}
+
+	if match == nil {
+		return nil, "", fmt.Errorf("no domain on the account matches %s", hostname)
+	}
+
+	host := strings.TrimSuffix(strings.TrimSuffix(hostname, strings.ToLower(match.Domain)), ".")
+	if host == "" {
+		host = "@"
+	}
+
+	return match, host, nil
+}
+
+// dynDNSRecord picks the address record the DDNS update produced. The record
+// type follows the address family of myip when it is known, otherwise the
+// type already in state, falling back to A.
+func dynDNSRecord(records []model.DnsRecord, recordType, myip string) *model.DnsRecord {
+	if ip := net.ParseIP(myip); ip != nil {
+		recordType = "A"
+		if ip.To4() == nil {
+			recordType = "AAAA"
+		}
+	} else if recordType == "" {
+		recordType = "A"
+	}
+
+	var fallback *model.DnsRecord
+	for i := range records {
+		switch records[i].Type {
+		case recordType:
+			return &records[i]
+		case "A", "AAAA":
+			if fallback == nil {
+				fallback = &records[i]
+			}
+		}
+	}
+
+	return fallback
+}
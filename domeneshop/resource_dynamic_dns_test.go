@@ -0,0 +1,78 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"testing"
+)
+
+func TestAccDynamicDNS_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dynamic_dns", []testAccStep{
+		{
+			// without myip the address of the caller is used
+			Config: map[string]interface{}{
+				"hostname": "edge.example.com",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if state.Attributes["myip"] != "127.0.0.1" || state.Attributes["type"] != "A" {
+					return fmt.Errorf("unexpected state %v", state.Attributes)
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"hostname": "edge.example.com",
+				"myip":     "192.0.2.10",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Host != "edge" || records[0].Data != "192.0.2.10" {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				return nil
+			},
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("records still exist: %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccDynamicDNS_domainGone(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	config := map[string]interface{}{
+		"hostname": "edge.example.com",
+		"myip":     "192.0.2.10",
+	}
+
+	// Once the domain has left the account, refresh drops the resource and
+	// the plan is to create it again, which the API refuses.
+	testAccResource(t, testAccProvider(t, server), "domeneshop_dynamic_dns", []testAccStep{
+		{
+			Config: config,
+		},
+		{
+			PreConfig: func() {
+				server.RemoveDomain(domain.Id)
+			},
+			Config:      config,
+			ExpectError: regexp.MustCompile("updating dynamic DNS"),
+		},
+	}, nil)
+}
@@ -0,0 +1,176 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+func resourceHTTPForward() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceHTTPForwardCreate,
+		ReadContext:   resourceHTTPForwardRead,
+		UpdateContext: resourceHTTPForwardUpdate,
+		DeleteContext: resourceHTTPForwardDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceHTTPForwardState,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"host": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"url": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"frame": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceHTTPForwardState(ctx context.Context, d *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+	domainId, host, err := parseForwardId(d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.Set("domain_id", domainId)
+	if err != nil {
+		return nil, err
+	}
+
+	err = d.Set("host", host)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHTTPForwardCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+	forward := httpForwardFromSchema(d)
+
+	err := client.CreateForward(ctx, domainId, forward)
+	if err != nil {
+		return apiError("creating HTTP forward", err)
+	}
+
+	d.SetId(forwardId(domainId, forward.Host))
+
+	// refresh state
+	diags = append(diags, resourceHTTPForwardRead(ctx, d, m)...)
+
+	return diags
+}
+
+func resourceHTTPForwardRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, host, err := parseForwardId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	forward, err := client.GetForward(ctx, domainId, host)
+	if err != nil {
+		return readError(d, "HTTP forward", err)
+	}
+
+	var errs []error
+	errs = append(errs, d.Set("domain_id", domainId))
+	errs = append(errs, d.Set("host", forward.Host))
+	errs = append(errs, d.Set("url", forward.Url))
+	errs = append(errs, d.Set("frame", forward.Frame))
+
+	for _, err = range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceHTTPForwardUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	if d.HasChanges("url", "frame") {
+		domainId := d.Get("domain_id").(int)
+
+		err := client.UpdateForward(ctx, domainId, httpForwardFromSchema(d))
+		if err != nil {
+			return apiError("updating HTTP forward", err)
+		}
+	}
+
+	return resourceHTTPForwardRead(ctx, d, m)
+}
+
+func resourceHTTPForwardDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, host, err := parseForwardId(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	err = client.DeleteForward(ctx, domainId, host)
+	if err != nil {
+		return deleteError("HTTP forward", err)
+	}
+
+	return diags
+}
+
+func httpForwardFromSchema(d *schema.ResourceData) *model.HttpForward {
+	return &model.HttpForward{
+		Host:  d.Get("host").(string),
+		Url:   d.Get("url").(string),
+		Frame: d.Get("frame").(bool),
+	}
+}
+
+// forwardId builds the `domain_id/host` ID used for forwards, which have no
+// numeric ID of their own.
+func forwardId(domainId int, host string) string {
+	return fmt.Sprintf("%d/%s", domainId, host)
+}
+
+func parseForwardId(id string) (int, string, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return 0, "", fmt.Errorf("unexpected format of ID (%s), expected domain_id/host", id)
+	}
+
+	domainId, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", err
+	}
+
+	return domainId, parts[1], nil
+}
@@ -0,0 +1,56 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"testing"
+)
+
+func TestAccHTTPForward_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_http_forward", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "www",
+				"url":       "https://example.org",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				if state.ID != fmt.Sprintf("%d/www", domain.Id) {
+					return fmt.Errorf("unexpected id %s", state.ID)
+				}
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "www",
+				"url":       "https://example.net",
+				"frame":     true,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				forwards := server.Forwards(domain.Id)
+				if len(forwards) != 1 || forwards[0].Url != "https://example.net" || !forwards[0].Frame {
+					return fmt.Errorf("unexpected forwards %+v", forwards)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState:   true,
+			ImportStateId: fmt.Sprintf("%d/www", domain.Id),
+		},
+	}, func() error {
+		if forwards := server.Forwards(domain.Id); len(forwards) != 0 {
+			return fmt.Errorf("forwards still exist: %+v", forwards)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,213 @@
+package domeneshop
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"net"
+	"strings"
+)
+
+// maxSPFLookups is the number of DNS lookups evaluating an SPF record may
+// take, from RFC 7208 section 4.6.4.
+const maxSPFLookups = 10
+
+var spfAllQualifiers = []string{"-all", "~all", "?all", "+all"}
+
+func resourceSPFRecord() *schema.Resource {
+	return spfRecordKind().resource()
+}
+
+func spfRecordKind() *txtRecordKind {
+	return &txtRecordKind{
+		name: "SPF record",
+		tag:  "v=spf1",
+		arguments: map[string]*schema.Schema{
+			"host": {
+				Type:      schema.TypeString,
+				Optional:  true,
+				Default:   "@",
+				ForceNew:  true,
+				StateFunc: normalizeDNSHostState,
+			},
+			"ip4": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSPFNetwork(4),
+				},
+			},
+			"ip6": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSPFNetwork(6),
+				},
+			},
+			"a": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSPFHost,
+				},
+			},
+			"mx": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSPFHost,
+				},
+			},
+			"include": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateSPFDomain,
+				},
+			},
+			"all": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "~all",
+				ValidateFunc: validateOneOf(spfAllQualifiers...),
+			},
+		},
+		host: func(get func(key string) interface{}) string {
+			return get("host").(string)
+		},
+		render:   renderSPFRecord,
+		validate: validateSPFLookups,
+		flatten:  flattenSPFRecord,
+	}
+}
+
+// renderSPFRecord returns the SPF policy of the arguments, with the
+// mechanisms that need no DNS lookup first.
+func renderSPFRecord(get func(key string) interface{}) string {
+	terms := []string{"v=spf1"}
+	for _, mechanism := range []string{"ip4", "ip6", "a", "mx", "include"} {
+		for _, raw := range get(mechanism).([]interface{}) {
+			domain, _ := raw.(string)
+			if (mechanism == "a" || mechanism == "mx") && domain == "@" {
+				terms = append(terms, mechanism)
+				continue
+			}
+			terms = append(terms, mechanism+":"+domain)
+		}
+	}
+	if all := get("all").(string); all != "" {
+		terms = append(terms, all)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// validateSPFLookups checks that the mechanisms of the policy take no more
+// DNS lookups than allowed. The lookups of included policies can't be
+// counted at plan time, so they are left for the included domains to get
+// right.
+func validateSPFLookups(get func(key string) interface{}) error {
+	lookups := 0
+	for _, mechanism := range []string{"a", "mx", "include"} {
+		lookups += len(get(mechanism).([]interface{}))
+	}
+
+	if lookups > maxSPFLookups {
+		return fmt.Errorf("the SPF record takes %d DNS lookups, more than the %d allowed: each a, mx and include takes one, "+
+			"and the lookups of the included records count too", lookups, maxSPFLookups)
+	}
+
+	return nil
+}
+
+// flattenSPFRecord returns the arguments of an SPF policy, or false if it uses
+// anything but the mechanisms they express, unqualified, and all.
+func flattenSPFRecord(host, data string) (map[string]interface{}, bool) {
+	terms := strings.Fields(data)
+	if len(terms) == 0 || terms[0] != "v=spf1" {
+		return nil, false
+	}
+
+	mechanisms := map[string][]interface{}{
+		"ip4":     {},
+		"ip6":     {},
+		"a":       {},
+		"mx":      {},
+		"include": {},
+	}
+	all := ""
+	for _, term := range terms[1:] {
+		if all != "" {
+			return nil, false
+		}
+
+		name, value := term, "@"
+		if i := strings.IndexByte(term, ':'); i >= 0 {
+			name, value = term[:i], term[i+1:]
+		}
+
+		switch {
+		case name == "a" || name == "mx":
+			mechanisms[name] = append(mechanisms[name], value)
+		case (name == "ip4" || name == "ip6" || name == "include") && value != "@":
+			mechanisms[name] = append(mechanisms[name], value)
+		case stringInSlice(term, spfAllQualifiers):
+			all = term
+		default:
+			return nil, false
+		}
+	}
+
+	arguments := map[string]interface{}{
+		"host": host,
+		"all":  all,
+	}
+	for name, values := range mechanisms {
+		arguments[name] = values
+	}
+
+	return arguments, true
+}
+
+// validateSPFNetwork returns a validator of the addresses or CIDR networks of
+// the ip4 or ip6 mechanisms.
+func validateSPFNetwork(version int) schema.SchemaValidateFunc {
+	return func(v interface{}, key string) ([]string, []error) {
+		value := v.(string)
+
+		address := value
+		if i := strings.IndexByte(value, '/'); i >= 0 {
+			if _, _, err := net.ParseCIDR(value); err != nil {
+				return nil, []error{fmt.Errorf("%s: %q is not a valid network: %w", key, value, err)}
+			}
+			address = value[:i]
+		}
+
+		if ip := net.ParseIP(address); ip == nil || strings.Contains(address, ":") != (version == 6) {
+			return nil, []error{fmt.Errorf("%s: %q is not an IPv%d address or network", key, value, version)}
+		}
+
+		return nil, nil
+	}
+}
+
+// validateSPFHost checks the domain of an a or mx mechanism, where @ stands
+// for the domain of the record.
+func validateSPFHost(v interface{}, key string) ([]string, []error) {
+	if v.(string) == "@" {
+		return nil, nil
+	}
+	return validateSPFDomain(v, key)
+}
+
+func validateSPFDomain(v interface{}, key string) ([]string, []error) {
+	if value := v.(string); !isHostname(value) {
+		return nil, []error{fmt.Errorf("%s: %q is not a valid domain", key, value)}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,148 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+func TestAccSPFRecord_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	var recordId int
+	testAccResource(t, testAccProvider(t, server), "domeneshop_spf_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"ip4":       []interface{}{"192.0.2.0/24"},
+				"a":         []interface{}{"@"},
+				"mx":        []interface{}{"@"},
+				"include":   []interface{}{"_spf.example.net"},
+				"all":       "-all",
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				expected := "v=spf1 ip4:192.0.2.0/24 a mx include:_spf.example.net -all"
+				if len(records) != 1 || records[0].Host != "@" || records[0].Type != "TXT" || records[0].Data != expected {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if state.Attributes["value"] != expected {
+					return fmt.Errorf("unexpected value %q", state.Attributes["value"])
+				}
+				recordId = records[0].Id
+				return nil
+			},
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"ip4":       []interface{}{"192.0.2.0/24"},
+				"ip6":       []interface{}{"2001:db8::/32"},
+				"mx":        []interface{}{"@", "mx.example.net"},
+				"include":   []interface{}{"_spf.example.net", "spf.example.org"},
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				expected := "v=spf1 ip4:192.0.2.0/24 ip6:2001:db8::/32 mx mx:mx.example.net include:_spf.example.net include:spf.example.org ~all"
+				if len(records) != 1 || records[0].Data != expected {
+					return fmt.Errorf("unexpected records %+v", records)
+				}
+				if records[0].Id != recordId {
+					return fmt.Errorf("expected record %d to be updated in place, got %d", recordId, records[0].Id)
+				}
+				return nil
+			},
+		},
+		{
+			ImportState: true,
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("records still exist: %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccSPFRecord_long(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+
+	var networks []interface{}
+	for i := 0; i < 20; i++ {
+		networks = append(networks, fmt.Sprintf("198.51.100.%d", i))
+	}
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_spf_record", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"host":      "mail",
+				"ip4":       networks,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 1 || records[0].Host != "mail" || !strings.HasPrefix(records[0].Data, `"v=spf1 ip4:198.51.100.0 `) {
+					return fmt.Errorf("expected the record to be split into quoted strings, got %+v", records)
+				}
+				if !strings.HasSuffix(state.Attributes["value"], "ip4:198.51.100.19 ~all") {
+					return fmt.Errorf("unexpected value %q", state.Attributes["value"])
+				}
+				return nil
+			},
+		},
+	}, nil)
+}
+
+func TestAccSPFRecord_invalid(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	existing := server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "TXT", Data: "v=spf1 -all", Ttl: 3600})
+
+	var includes []interface{}
+	for i := 0; i < 9; i++ {
+		includes = append(includes, fmt.Sprintf("spf%d.example.net", i))
+	}
+
+	cases := map[string]struct {
+		config map[string]interface{}
+		err    string
+	}{
+		"lookups": {
+			map[string]interface{}{"host": "sub", "a": []interface{}{"@"}, "mx": []interface{}{"@"}, "include": includes},
+			"takes 11 DNS lookups, more than the 10 allowed",
+		},
+		"ip4":     {map[string]interface{}{"host": "sub", "ip4": []interface{}{"2001:db8::1"}}, "not an IPv4 address"},
+		"ip6":     {map[string]interface{}{"host": "sub", "ip6": []interface{}{"192.0.2.0/24"}}, "not an IPv6 address"},
+		"network": {map[string]interface{}{"host": "sub", "ip4": []interface{}{"192.0.2.0/33"}}, "not a valid network"},
+		"include": {map[string]interface{}{"host": "sub", "include": []interface{}{"@"}}, "not a valid domain"},
+		"all":     {map[string]interface{}{"host": "sub", "all": "all"}, "is not one of"},
+		"exists":  {map[string]interface{}{"ip4": []interface{}{"192.0.2.1"}}, fmt.Sprintf("already exists as DNS record %d/%d", domain.Id, existing)},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.config["domain_id"] = domain.Id
+			testAccResource(t, testAccProvider(t, server), "domeneshop_spf_record", []testAccStep{
+				{
+					Config:      tc.config,
+					ExpectError: regexp.MustCompile(regexp.QuoteMeta(tc.err)),
+				},
+			}, nil)
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package domeneshop
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// txtRecordKind describes a resource managing a single TXT record, like an
+// SPF policy, whose data is rendered from structured arguments. The rendered
+// data is exported as value.
+type txtRecordKind struct {
+	// name is how the record is called in messages, i.e. "SPF record".
+	name string
+	// tag starts the data of every record of the kind, i.e. "v=spf1", to
+	// find a record of the kind already at the host.
+	tag string
+	// arguments is the schema of the structured arguments.
+	arguments map[string]*schema.Schema
+	// host returns the host of the record for the arguments.
+	host func(get func(key string) interface{}) string
+	// render returns the data of the record for the arguments.
+	render func(get func(key string) interface{}) string
+	// validate checks the arguments at plan time, when set.
+	validate func(get func(key string) interface{}) error
+	// flatten returns the arguments of a record with host and data, or false
+	// if they can't express it.
+	flatten func(host, data string) (map[string]interface{}, bool)
+}
+
+func (k *txtRecordKind) resource() *schema.Resource {
+	resource := &schema.Resource{
+		CreateContext: k.create,
+		ReadContext:   k.read,
+		UpdateContext: k.update,
+		DeleteContext: k.delete,
+		CustomizeDiff: k.customizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDNSRecordState,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"ttl": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validateTTL,
+			},
+			"value": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+
+	for key, argument := range k.arguments {
+		resource.Schema[key] = argument
+	}
+
+	return resource
+}
+
+// customizeDiff validates the arguments and renders value from them, so the
+// plan shows the record as it will be written.
+func (k *txtRecordKind) customizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	for key := range k.arguments {
+		if !d.NewValueKnown(key) {
+			return d.SetNewComputed("value")
+		}
+	}
+
+	if k.validate != nil {
+		if err := k.validate(d.Get); err != nil {
+			return err
+		}
+	}
+
+	data := k.render(d.Get)
+	if err := validateTXTLength(data); err != nil {
+		return err
+	}
+
+	if d.Get("value").(string) != data {
+		return d.SetNew("value", data)
+	}
+
+	return nil
+}
+
+func (k *txtRecordKind) create(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+	record := k.record(d)
+
+	existing, err := client.ListRecords(ctx, domainId, record.Host, "TXT")
+	if err != nil {
+		return apiError("listing DNS records", err)
+	}
+	for _, other := range filterDNSRecords(existing, record.Host, "TXT") {
+		if k.isKind(other.Data) {
+			return diag.Errorf("%s at %s already exists as DNS record %s, import it instead of creating another", k.name, record.Host, dnsRecordId(domainId, other.Id))
+		}
+	}
+
+	diags := createDNSRecord(ctx, d, m, k.name, record)
+	if diags.HasError() {
+		return diags
+	}
+	// The data is rendered from the arguments, after validation, so a long
+	// record is only warned about here.
+	diags = append(diags, dnsRecordTXTWarnings(*record)...)
+
+	// refresh state
+	return append(diags, k.read(ctx, d, m)...)
+}
+
+func (k *txtRecordKind) read(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	record, diags := readDNSRecord(ctx, d, m, k.name)
+	if record == nil {
+		return diags
+	}
+	if record.Type != "TXT" {
+		return diag.Errorf("DNS record %s is of type %s, not TXT", d.Id(), record.Type)
+	}
+
+	data := normalizeDNSRecordData("TXT", record.Data)
+
+	var errs []error
+	errs = append(errs, d.Set("value", data))
+
+	// A record changed into something the arguments can't express keeps
+	// them as they were, and shows up as a change of value.
+	if arguments, ok := k.flatten(normalizeDNSHost(record.Host), data); ok {
+		for key, value := range arguments {
+			errs = append(errs, d.Set(key, value))
+		}
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func (k *txtRecordKind) update(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	if d.HasChange("ttl") || d.HasChange("value") || d.HasChanges(k.argumentKeys()...) {
+		record := k.record(d)
+		diags = updateDNSRecord(ctx, d, m, k.name, record)
+		if diags.HasError() {
+			return diags
+		}
+		diags = append(diags, dnsRecordTXTWarnings(*record)...)
+	}
+
+	return append(diags, k.read(ctx, d, m)...)
+}
+
+func (k *txtRecordKind) delete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	return deleteDNSRecord(ctx, d, m, k.name)
+}
+
+// record returns the TXT record the arguments describe.
+func (k *txtRecordKind) record(d *schema.ResourceData) *model.DnsRecord {
+	return &model.DnsRecord{
+		Host: normalizeDNSHost(k.host(d.Get)),
+		Ttl:  d.Get("ttl").(int),
+		Type: "TXT",
+		Data: encodeTXTData(k.render(d.Get)),
+	}
+}
+
+// isKind reports whether TXT data is a record of the kind.
+func (k *txtRecordKind) isKind(data string) bool {
+	data = normalizeDNSRecordData("TXT", data)
+	return data == k.tag || strings.HasPrefix(data, k.tag+" ") || strings.HasPrefix(data, k.tag+";")
+}
+
+func (k *txtRecordKind) argumentKeys() []string {
+	keys := make([]string, 0, len(k.arguments))
+	for key := range k.arguments {
+		keys = append(keys, key)
+	}
+	return keys
+}
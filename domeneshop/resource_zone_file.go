@@ -0,0 +1,210 @@
+package domeneshop
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/client"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"terraform-provider-domeneshop/domeneshop/zonefile"
+)
+
+func resourceZoneFile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceZoneFileCreate,
+		ReadContext:   resourceZoneFileRead,
+		UpdateContext: resourceZoneFileUpdate,
+		DeleteContext: resourceZoneFileDelete,
+		CustomizeDiff: resourceZoneFileCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDNSZoneState,
+		},
+		Schema: map[string]*schema.Schema{
+			"domain_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+				ForceNew: true,
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ignore": dnsZoneIgnoreSchema(),
+		},
+	}
+}
+
+// resourceZoneFileCustomizeDiff parses the zone file at plan time, so syntax
+// errors show up before anything is changed.
+func resourceZoneFileCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("domain_id") || !d.NewValueKnown("content") || !d.HasChange("content") {
+		return nil
+	}
+
+	client := m.(client.API)
+
+	domain, err := client.GetDomain(ctx, d.Get("domain_id").(int))
+	if err != nil {
+		return err
+	}
+
+	_, err = zoneFileRecords(domain.Domain, d.Get("content").(string))
+	if err != nil {
+		return fmt.Errorf("content: %w", err)
+	}
+
+	return nil
+}
+
+func resourceZoneFileCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	domainId := d.Get("domain_id").(int)
+
+	diags = syncZoneFile(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+
+	d.SetId(strconv.Itoa(domainId))
+
+	// refresh state
+	diags = append(diags, resourceZoneFileRead(ctx, d, m)...)
+
+	return diags
+}
+
+func resourceZoneFileRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	domain, err := client.GetDomain(ctx, domainId)
+	if err != nil {
+		return readError(d, "domain", err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return readError(d, "DNS records", err)
+	}
+	records = dnsZoneRecords(records, d.Get("ignore").([]interface{}))
+
+	// Keep the zone file as written while it matches the records, and show
+	// the records as a zone file when they have drifted.
+	content := d.Get("content").(string)
+	desired, err := zoneFileRecords(domain.Domain, content)
+	if err != nil || !dnsRecordsEqual(records, desired) {
+		content = zonefile.Render(domain.Domain, joinTXTData(records))
+	}
+
+	var errs []error
+	errs = append(errs, d.Set("domain_id", domainId))
+	errs = append(errs, d.Set("content", content))
+
+	for _, err = range errs {
+		if err != nil {
+			diags = append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return diags
+}
+
+func resourceZoneFileUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChanges("content", "ignore") {
+		if diags := syncZoneFile(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceZoneFileRead(ctx, d, m)
+}
+
+func resourceZoneFileDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	client := m.(client.API)
+
+	domainId, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	records, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return deleteError("DNS records", err)
+	}
+
+	for _, record := range dnsZoneRecords(records, d.Get("ignore").([]interface{})) {
+		err = client.DeleteRecord(ctx, domainId, record.Id)
+		if err != nil {
+			diags = append(diags, deleteError("DNS record", err)...)
+		}
+	}
+
+	return diags
+}
+
+// syncZoneFile makes the records of the domain, except the ignored ones, match
+// the zone file exactly.
+func syncZoneFile(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(client.API)
+
+	domainId := d.Get("domain_id").(int)
+	ignore := d.Get("ignore").([]interface{})
+
+	domain, err := client.GetDomain(ctx, domainId)
+	if err != nil {
+		return apiError("reading domain", err)
+	}
+
+	desired, err := zoneFileRecords(domain.Domain, d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("parsing zone file: %v", err)
+	}
+	for _, record := range desired {
+		if dnsRecordIgnored(record, ignore) {
+			return diag.Errorf("record %s %s is matched by an ignore block", record.Host, record.Type)
+		}
+	}
+
+	existing, err := client.ListRecords(ctx, domainId, "", "")
+	if err != nil {
+		return apiError("listing DNS records", err)
+	}
+
+	return applyDNSRecordChanges(ctx, client, domainId, dnsZoneRecords(existing, ignore), desired)
+}
+
+// zoneFileRecords parses content, leaving out the NS records of the domain
+// itself, which Domeneshop manages.
+func zoneFileRecords(domain, content string) ([]model.DnsRecord, error) {
+	records, err := zonefile.Parse(strings.NewReader(content), domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var managed []model.DnsRecord
+	for _, record := range records {
+		if !isApexNS(record) {
+			managed = append(managed, record)
+		}
+	}
+	return managed, nil
+}
+
+// dnsRecordsEqual reports whether a and b hold the same records, ignoring IDs
+// and order.
+func dnsRecordsEqual(a, b []model.DnsRecord) bool {
+	creates, updates, deletes := diffDNSRecords(a, b)
+	return len(creates) == 0 && len(updates) == 0 && len(deletes) == 0
+}
@@ -0,0 +1,130 @@
+package domeneshop_test
+
+import (
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"regexp"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/fake"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"testing"
+)
+
+const testAccZoneFile = `$ORIGIN example.com.
+$TTL 3600
+@	IN	SOA	ns1.hyp.net. hostmaster.example.com. ( 1 3600 900 1209600 300 )
+	IN	NS	ns1.hyp.net.
+	IN	A	192.0.2.1
+	IN	MX	10 mx
+www	300	IN	CNAME	@
+txt		IN	TXT	"v=spf1 " "-all"
+`
+
+func TestAccZoneFile_basic(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "legacy", Type: "A", Data: "192.0.2.9", Ttl: 3600})
+
+	testAccResource(t, testAccProvider(t, server), "domeneshop_zone_file", []testAccStep{
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"content":   "@ IN A",
+			},
+			ExpectError: regexp.MustCompile("content: line 1"),
+		},
+		{
+			// A TTL the API refuses fails the plan, before any record is
+			// changed.
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"content":   "www 300 A 192.0.2.1\nmail 30 A 192.0.2.2\n",
+			},
+			ExpectError: regexp.MustCompile("content: line 2: A record mail: TTL must be between 60 and 604800 seconds"),
+		},
+		{
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"content":   testAccZoneFile,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				records := server.Records(domain.Id)
+				if len(records) != 4 {
+					return fmt.Errorf("expected 4 records, got %+v", records)
+				}
+				for _, record := range records {
+					switch {
+					case record.Host == "legacy":
+						return fmt.Errorf("undeclared record was not deleted: %+v", record)
+					case record.Type == "TXT" && record.Data != "v=spf1 -all":
+						return fmt.Errorf("unexpected TXT record %+v", record)
+					case record.Type == "CNAME" && (record.Data != "example.com" || record.Ttl != 300):
+						return fmt.Errorf("unexpected CNAME record %+v", record)
+					}
+				}
+				if state.Attributes["content"] != testAccZoneFile {
+					return fmt.Errorf("expected the zone file to be kept as written, got:\n%s", state.Attributes["content"])
+				}
+				return nil
+			},
+		},
+		{
+			// Records added behind Terraform's back show up as a diff.
+			PreConfig: func() {
+				server.AddRecord(domain.Id, model.DnsRecord{Host: "manual", Type: "A", Data: "192.0.2.10", Ttl: 3600})
+			},
+			Config: map[string]interface{}{
+				"domain_id": domain.Id,
+				"content":   testAccZoneFile,
+			},
+			Check: func(state *terraform.InstanceState) error {
+				for _, record := range server.Records(domain.Id) {
+					if record.Host == "manual" {
+						return fmt.Errorf("undeclared record was not deleted: %+v", record)
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ImportState:             true,
+			ImportStateVerifyIgnore: []string{"content"},
+		},
+	}, func() error {
+		if records := server.Records(domain.Id); len(records) != 0 {
+			return fmt.Errorf("records still exist: %+v", records)
+		}
+		return nil
+	})
+}
+
+func TestAccZoneFileDataSource(t *testing.T) {
+	testAccPreCheck(t)
+
+	server := fake.NewServer()
+	defer server.Close()
+	domain := server.AddDomain("example.com")
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "www", Type: "A", Data: "192.0.2.1", Ttl: 300})
+	server.AddRecord(domain.Id, model.DnsRecord{Host: "@", Type: "MX", Data: "mx.example.com", Priority: "10", Ttl: 3600})
+
+	state, err := testAccDataSource(t, testAccProvider(t, server), "domeneshop_zone_file", map[string]interface{}{
+		"domain_id": domain.Id,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content := state.Attributes["content"]
+	for _, line := range []string{
+		"$ORIGIN example.com.",
+		"@\t3600\tIN\tMX\t10 mx.example.com.",
+		"www\t300\tIN\tA\t192.0.2.1",
+	} {
+		if !strings.Contains(content, line+"\n") {
+			t.Errorf("expected the zone file to contain %q, got:\n%s", line, content)
+		}
+	}
+}
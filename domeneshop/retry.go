@@ -0,0 +1,190 @@
+package domeneshop
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// RetryTransport retries requests that fail with a connection error, a 5xx
+// or a 429 response, with exponential backoff and jitter, honouring
+// Retry-After. Only idempotent methods are retried, except that any request
+// rejected with 429, or failing before it was written to the connection, is
+// safe to retry too. A response asking to wait longer than MaxBackoff is
+// returned as is, rather than stalling the caller.
+type RetryTransport struct {
+	T http.RoundTripper
+	// MaxRetries is the number of retries after the first attempt.
+	MaxRetries int
+	// Timeout limits each attempt, when set.
+	Timeout time.Duration
+	// MinBackoff and MaxBackoff bound the wait between attempts.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (rt *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, cancel, written, err := rt.prepare(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := rt.T.RoundTrip(attemptReq)
+
+		retry := attempt < rt.MaxRetries && req.Context().Err() == nil
+		var wait time.Duration
+		switch {
+		case err != nil:
+			retry = retry && (isIdempotent(req.Method) || atomic.LoadInt32(written) == 0)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			wait = retryAfter(resp)
+		case resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented:
+			retry = retry && isIdempotent(req.Method)
+			wait = retryAfter(resp)
+		default:
+			retry = false
+		}
+		if retry && wait > rt.MaxBackoff {
+			log.Printf("[WARN] %s %s returned %d with Retry-After of %v, longer than the %v allowed, not retrying", req.Method, req.URL, resp.StatusCode, wait, rt.MaxBackoff)
+			retry = false
+		}
+
+		if !retry {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		if err != nil {
+			log.Printf("[DEBUG] %s %s failed, retrying: %v", req.Method, req.URL, err)
+		} else {
+			log.Printf("[DEBUG] %s %s returned %d, retrying", req.Method, req.URL, resp.StatusCode)
+			_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+			_ = resp.Body.Close()
+		}
+		cancel()
+
+		if wait == 0 {
+			wait = rt.backoff(attempt)
+		}
+		if err := sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepare returns a copy of req for an attempt, with a fresh body, the
+// per-attempt timeout and a trace reporting whether the request was written.
+func (rt *RetryTransport) prepare(req *http.Request, attempt int) (*http.Request, context.CancelFunc, *int32, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if rt.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, rt.Timeout)
+	}
+
+	written := new(int32)
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaders: func() {
+			atomic.StoreInt32(written, 1)
+		},
+	})
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			cancel()
+			return nil, nil, nil, errNoGetBody
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, written, nil
+}
+
+// backoff returns the wait before retry number attempt+1: exponential from
+// MinBackoff, capped at MaxBackoff, with the upper half randomized.
+func (rt *RetryTransport) backoff(attempt int) time.Duration {
+	wait := rt.MinBackoff
+	for i := 0; i < attempt && wait < rt.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > rt.MaxBackoff {
+		wait = rt.MaxBackoff
+	}
+	if wait <= 0 {
+		return 0
+	}
+
+	half := wait / 2
+	return half + time.Duration(rand.Int63n(int64(wait-half)+1))
+}
+
+// retryAfter returns the wait asked for by the Retry-After header of resp, in
+// seconds or as an HTTP date, or 0 if there is none.
+func retryAfter(resp *http.Response) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(date); wait > 0 {
+			return wait
+		}
+	}
+
+	return 0
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelBody releases the per-attempt context once the body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+var errNoGetBody = errors.New("request body can't be replayed for a retry")
@@ -0,0 +1,186 @@
+package domeneshop_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"terraform-provider-domeneshop/domeneshop"
+	"testing"
+	"time"
+)
+
+func testRetryClient(maxRetries int) *http.Client {
+	return &http.Client{
+		Transport: &domeneshop.RetryTransport{
+			T:          http.DefaultTransport,
+			MaxRetries: maxRetries,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: 5 * time.Millisecond,
+		},
+	}
+}
+
+// testRetryServer responds with the given status codes in order, then 200.
+func testRetryServer(t *testing.T, statuses ...int) (*httptest.Server, *int32) {
+	attempts := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if r.Method == "PUT" && string(body) != "payload" {
+			t.Errorf("attempt %d: expected the body to be replayed, got %q", atomic.LoadInt32(attempts), body)
+		}
+
+		attempt := int(atomic.AddInt32(attempts, 1)) - 1
+		if attempt < len(statuses) {
+			w.WriteHeader(statuses[attempt])
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	return server, attempts
+}
+
+func TestRetryTransport(t *testing.T) {
+	cases := map[string]struct {
+		method     string
+		statuses   []int
+		maxRetries int
+		status     int
+		attempts   int32
+	}{
+		"GET 5xx":              {method: "GET", statuses: []int{503, 502}, maxRetries: 3, status: 200, attempts: 3},
+		"GET retries run out":  {method: "GET", statuses: []int{500, 500, 500}, maxRetries: 2, status: 500, attempts: 3},
+		"PUT replays body":     {method: "PUT", statuses: []int{504}, maxRetries: 3, status: 200, attempts: 2},
+		"POST 5xx not retried": {method: "POST", statuses: []int{500}, maxRetries: 3, status: 500, attempts: 1},
+		"POST 429":             {method: "POST", statuses: []int{429}, maxRetries: 3, status: 200, attempts: 2},
+		"4xx not retried":      {method: "GET", statuses: []int{404}, maxRetries: 3, status: 404, attempts: 1},
+		"no retries":           {method: "GET", statuses: []int{503}, maxRetries: 0, status: 503, attempts: 1},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server, attempts := testRetryServer(t, tc.statuses...)
+			defer server.Close()
+
+			req, err := http.NewRequest(tc.method, server.URL, bytes.NewBufferString("payload"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := testRetryClient(tc.maxRetries).Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tc.status {
+				t.Errorf("expected status %d, got %d", tc.status, resp.StatusCode)
+			}
+			if got := atomic.LoadInt32(attempts); got != tc.attempts {
+				t.Errorf("expected %d attempts, got %d", tc.attempts, got)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_retryAfter(t *testing.T) {
+	attempts := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(attempts, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := testRetryClient(3)
+	client.Transport.(*domeneshop.RetryTransport).MaxBackoff = 2 * time.Second
+
+	start := time.Now()
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("expected to wait for Retry-After, retried after %v", elapsed)
+	}
+}
+
+func TestRetryTransport_retryAfterTooLong(t *testing.T) {
+	cases := map[string]string{
+		"seconds": "3600",
+		"date":    time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+	}
+
+	for name, value := range cases {
+		t.Run(name, func(t *testing.T) {
+			attempts := new(int32)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(attempts, 1)
+				w.Header().Set("Retry-After", value)
+				w.WriteHeader(http.StatusTooManyRequests)
+			}))
+			defer server.Close()
+
+			start := time.Now()
+			resp, err := testRetryClient(3).Get(server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			// Waiting longer than MaxBackoff gives up at once.
+			if resp.StatusCode != http.StatusTooManyRequests {
+				t.Errorf("expected status 429, got %d", resp.StatusCode)
+			}
+			if got := atomic.LoadInt32(attempts); got != 1 {
+				t.Errorf("expected 1 attempt, got %d", got)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("expected no wait, returned after %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestRetryTransport_connectionFailure(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	// Nothing was sent on a refused connection, so even POST is retried.
+	attempts := new(int32)
+	client := &http.Client{
+		Transport: &domeneshop.RetryTransport{
+			T: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				atomic.AddInt32(attempts, 1)
+				return http.DefaultTransport.RoundTrip(req)
+			}),
+			MaxRetries: 2,
+			MinBackoff: time.Millisecond,
+			MaxBackoff: time.Millisecond,
+		},
+	}
+
+	_, err := client.Post(url, "application/json", bytes.NewBufferString("{}"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if got := atomic.LoadInt32(attempts); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
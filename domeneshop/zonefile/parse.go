@@ -0,0 +1,450 @@
+// Package zonefile reads and writes DNS records in the RFC 1035 master file
+// format used by BIND.
+package zonefile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+)
+
+// DefaultTTL is used for records without a TTL when the file has no $TTL.
+const DefaultTTL = 3600
+
+// Parse reads the records of the zone file in r for domain. Owner names are
+// made relative to domain, with @ for the domain itself, and hostnames in the
+// record data are made absolute without the trailing dot. $ORIGIN defaults to
+// domain. SOA records are skipped.
+func Parse(r io.Reader, domain string) ([]model.DnsRecord, error) {
+	domain = canonical(domain)
+
+	p := parser{
+		domain: domain,
+		origin: domain,
+		ttl:    DefaultTTL,
+	}
+
+	entries, err := lex(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []model.DnsRecord
+	for _, entry := range entries {
+		record, err := p.parse(entry)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", entry.line, err)
+		}
+		if record != nil {
+			records = append(records, *record)
+		}
+	}
+
+	return records, nil
+}
+
+type parser struct {
+	domain string
+	origin string
+	ttl    int
+	owner  string
+}
+
+func (p *parser) parse(e entry) (*model.DnsRecord, error) {
+	tokens := e.tokens
+
+	if !tokens[0].quoted && strings.HasPrefix(tokens[0].value, "$") {
+		return nil, p.directive(tokens)
+	}
+
+	if !e.indented {
+		p.owner = p.absolute(tokens[0].value)
+		tokens = tokens[1:]
+	}
+	if p.owner == "" {
+		return nil, fmt.Errorf("record without owner name")
+	}
+
+	ttl := p.ttl
+	for len(tokens) > 0 && !tokens[0].quoted {
+		value := strings.ToUpper(tokens[0].value)
+		if value == "IN" {
+			tokens = tokens[1:]
+			continue
+		}
+		if value == "CH" || value == "HS" || value == "CS" {
+			return nil, fmt.Errorf("unsupported class %s", tokens[0].value)
+		}
+		if seconds, err := parseTTL(tokens[0].value); err == nil {
+			ttl = seconds
+			tokens = tokens[1:]
+			continue
+		}
+		break
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("missing record type")
+	}
+
+	recordType := strings.ToUpper(tokens[0].value)
+	rdata := tokens[1:]
+
+	if recordType == "SOA" {
+		return nil, nil
+	}
+
+	host, err := p.host(p.owner)
+	if err != nil {
+		return nil, err
+	}
+	if err := model.CheckTTL(ttl); err != nil {
+		return nil, fmt.Errorf("%s record %s: TTL %w", recordType, host, err)
+	}
+
+	record := &model.DnsRecord{
+		Host: host,
+		Ttl:  ttl,
+		Type: recordType,
+	}
+
+	if err := p.rdata(record, rdata); err != nil {
+		return nil, fmt.Errorf("%s record %s: %w", recordType, host, err)
+	}
+
+	return record, nil
+}
+
+func (p *parser) directive(tokens []token) error {
+	switch strings.ToUpper(tokens[0].value) {
+	case "$ORIGIN":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$ORIGIN takes one domain name")
+		}
+		p.origin = p.absolute(tokens[1].value)
+	case "$TTL":
+		if len(tokens) != 2 {
+			return fmt.Errorf("$TTL takes one TTL")
+		}
+		ttl, err := parseTTL(tokens[1].value)
+		if err != nil {
+			return fmt.Errorf("$TTL: %w", err)
+		}
+		p.ttl = ttl
+	default:
+		return fmt.Errorf("unsupported directive %s", tokens[0].value)
+	}
+
+	return nil
+}
+
+func (p *parser) rdata(record *model.DnsRecord, rdata []token) error {
+	fields := func(count int) ([]string, error) {
+		if len(rdata) != count {
+			return nil, fmt.Errorf("expected %d fields, got %d", count, len(rdata))
+		}
+		values := make([]string, count)
+		for i, t := range rdata {
+			values[i] = t.value
+		}
+		return values, nil
+	}
+
+	switch record.Type {
+	case "A", "AAAA":
+		values, err := fields(1)
+		if err != nil {
+			return err
+		}
+		record.Data = values[0]
+	case "ANAME", "CNAME", "NS":
+		values, err := fields(1)
+		if err != nil {
+			return err
+		}
+		record.Data = p.target(values[0])
+	case "MX":
+		values, err := fields(2)
+		if err != nil {
+			return err
+		}
+		if _, err := strconv.Atoi(values[0]); err != nil {
+			return fmt.Errorf("preference: %w", err)
+		}
+		record.Priority = values[0]
+		record.Data = p.target(values[1])
+	case "SRV":
+		values, err := fields(4)
+		if err != nil {
+			return err
+		}
+		numbers, err := atois(values[:3])
+		if err != nil {
+			return err
+		}
+		record.Priority = values[0]
+		record.Weight = numbers[1]
+		record.Port = numbers[2]
+		record.Data = p.target(values[3])
+	case "TXT":
+		if len(rdata) == 0 {
+			return fmt.Errorf("expected at least one string")
+		}
+		var data strings.Builder
+		for _, t := range rdata {
+			data.WriteString(t.value)
+		}
+		record.Data = data.String()
+	case "CAA":
+		values, err := fields(3)
+		if err != nil {
+			return err
+		}
+		flags, err := strconv.Atoi(values[0])
+		if err != nil {
+			return fmt.Errorf("flags: %w", err)
+		}
+		tag := -1
+		for i, name := range model.CAATags {
+			if strings.EqualFold(values[1], name) {
+				tag = i
+			}
+		}
+		if tag < 0 {
+			return fmt.Errorf("unsupported tag %s", values[1])
+		}
+		record.Flags = &flags
+		record.Tag = &tag
+		record.Data = values[2]
+	case "DS":
+		if len(rdata) < 4 {
+			return fmt.Errorf("expected at least 4 fields, got %d", len(rdata))
+		}
+		numbers, err := atois([]string{rdata[0].value, rdata[1].value, rdata[2].value})
+		if err != nil {
+			return err
+		}
+		record.Tag = &numbers[0]
+		record.Alg = &numbers[1]
+		record.Digest = &numbers[2]
+		record.Data = joinTokens(rdata[3:])
+	case "TLSA":
+		if len(rdata) < 4 {
+			return fmt.Errorf("expected at least 4 fields, got %d", len(rdata))
+		}
+		numbers, err := atois([]string{rdata[0].value, rdata[1].value, rdata[2].value})
+		if err != nil {
+			return err
+		}
+		record.Usage = &numbers[0]
+		record.Selector = &numbers[1]
+		record.Dtype = &numbers[2]
+		record.Data = strings.ToLower(joinTokens(rdata[3:]))
+	default:
+		return fmt.Errorf("unsupported record type")
+	}
+
+	return nil
+}
+
+// absolute returns name as an absolute name without the trailing dot, taking
+// relative names to be relative to the current origin.
+func (p *parser) absolute(name string) string {
+	if name == "@" {
+		return p.origin
+	}
+	if strings.HasSuffix(name, ".") {
+		return canonical(name)
+	}
+	return canonical(name + "." + p.origin)
+}
+
+// target returns the hostname in record data as an absolute name without the
+// trailing dot.
+func (p *parser) target(name string) string {
+	return p.absolute(name)
+}
+
+// host returns the absolute name as a host relative to the domain.
+func (p *parser) host(name string) (string, error) {
+	if name == p.domain {
+		return "@", nil
+	}
+	if strings.HasSuffix(name, "."+p.domain) {
+		return strings.TrimSuffix(name, "."+p.domain), nil
+	}
+	return "", fmt.Errorf("%s is outside of %s", name, p.domain)
+}
+
+// canonical lower cases name and removes the trailing dot.
+func canonical(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// parseTTL parses a TTL in seconds, or in BIND's units, i.e. 1h30m.
+func parseTTL(value string) (int, error) {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return seconds, nil
+	}
+
+	units := map[byte]int{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+	total, number, digits := 0, 0, 0
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+			digits++
+		case units[c|0x20] != 0 && digits > 0:
+			total += number * units[c|0x20]
+			number, digits = 0, 0
+		default:
+			return 0, fmt.Errorf("invalid TTL %q", value)
+		}
+	}
+	if digits > 0 || total == 0 && value != "0" {
+		return 0, fmt.Errorf("invalid TTL %q", value)
+	}
+
+	return total, nil
+}
+
+func atois(values []string) ([]int, error) {
+	numbers := make([]int, len(values))
+	for i, value := range values {
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+	return numbers, nil
+}
+
+func joinTokens(tokens []token) string {
+	var joined strings.Builder
+	for _, t := range tokens {
+		joined.WriteString(t.value)
+	}
+	return joined.String()
+}
+
+type token struct {
+	value  string
+	quoted bool
+}
+
+// entry is one logical line of the file, which can span several physical
+// lines inside parentheses.
+type entry struct {
+	line     int
+	indented bool
+	tokens   []token
+}
+
+// lex splits a zone file into entries, removing comments and unescaping
+// quoted strings.
+func lex(r io.Reader) ([]entry, error) {
+	var entries []entry
+	var current *entry
+	depth := 0
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+
+		if depth == 0 {
+			if current != nil && len(current.tokens) > 0 {
+				entries = append(entries, *current)
+			}
+			current = &entry{
+				line:     line,
+				indented: len(text) > 0 && (text[0] == ' ' || text[0] == '\t'),
+			}
+		}
+
+		for i := 0; i < len(text); {
+			c := text[i]
+			switch {
+			case c == ';':
+				i = len(text)
+			case c == ' ' || c == '\t' || c == '\r':
+				i++
+			case c == '(':
+				depth++
+				i++
+			case c == ')':
+				if depth == 0 {
+					return nil, fmt.Errorf("line %d: unbalanced )", line)
+				}
+				depth--
+				i++
+			case c == '"':
+				value, end, err := unquote(text, i+1)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				current.tokens = append(current.tokens, token{value: value, quoted: true})
+				i = end
+			default:
+				start := i
+				for i < len(text) && !strings.ContainsRune(" \t\r;()\"", rune(text[i])) {
+					if text[i] == '\\' {
+						i++
+					}
+					i++
+				}
+				current.tokens = append(current.tokens, token{value: text[start:i]})
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("line %d: unbalanced (", current.line)
+	}
+	if current != nil && len(current.tokens) > 0 {
+		entries = append(entries, *current)
+	}
+
+	return entries, nil
+}
+
+// unquote reads the quoted string in text starting at start, just after the
+// opening quote, and returns its value and the index after the closing quote.
+func unquote(text string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+3 < len(text) && isDigit(text[i+1]) && isDigit(text[i+2]) && isDigit(text[i+3]) {
+				code, _ := strconv.Atoi(text[i+1 : i+4])
+				if code > 255 {
+					return "", 0, fmt.Errorf("invalid escape \\%s", text[i+1:i+4])
+				}
+				value.WriteByte(byte(code))
+				i += 3
+			} else if i+1 < len(text) {
+				value.WriteByte(text[i+1])
+				i++
+			}
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string")
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,138 @@
+package zonefile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"unicode/utf8"
+)
+
+// MaxStringLength is the longest character-string a TXT record can hold.
+// Longer data is split into several.
+const MaxStringLength = 255
+
+// Render writes records of domain as a zone file, with one line per record
+// sorted by host, type and data. Hostnames in record data without a trailing
+// dot are taken to be absolute.
+func Render(domain string, records []model.DnsRecord) string {
+	domain = canonical(domain)
+
+	sorted := make([]model.DnsRecord, len(records))
+	copy(sorted, records)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Host != b.Host {
+			return hostOrder(a.Host) < hostOrder(b.Host)
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Data < b.Data
+	})
+
+	var zone strings.Builder
+	fmt.Fprintf(&zone, "$ORIGIN %s.\n", domain)
+	fmt.Fprintf(&zone, "$TTL %d\n", DefaultTTL)
+	for _, record := range sorted {
+		ttl := record.Ttl
+		if ttl == 0 {
+			ttl = DefaultTTL
+		}
+		fmt.Fprintf(&zone, "%s\t%d\tIN\t%s\t%s\n", record.Host, ttl, record.Type, rdata(record))
+	}
+
+	return zone.String()
+}
+
+// hostOrder sorts @ before everything else.
+func hostOrder(host string) string {
+	if host == "@" {
+		return ""
+	}
+	return host
+}
+
+func rdata(record model.DnsRecord) string {
+	switch record.Type {
+	case "ANAME", "CNAME", "NS":
+		return fqdn(record.Data)
+	case "MX":
+		return fmt.Sprintf("%s %s", record.Priority, fqdn(record.Data))
+	case "SRV":
+		return fmt.Sprintf("%s %d %d %s", record.Priority, record.Weight, record.Port, fqdn(record.Data))
+	case "TXT":
+		return quoteTXT(record.Data)
+	case "CAA":
+		tag := strconv.Itoa(model.IntValue(record.Tag))
+		if value := model.IntValue(record.Tag); value >= 0 && value < len(model.CAATags) {
+			tag = model.CAATags[value]
+		}
+		return fmt.Sprintf("%d %s %s", model.IntValue(record.Flags), tag, quote(record.Data))
+	case "DS":
+		return fmt.Sprintf("%d %d %d %s", model.IntValue(record.Tag), model.IntValue(record.Alg), model.IntValue(record.Digest), record.Data)
+	case "TLSA":
+		return fmt.Sprintf("%d %d %d %s", model.IntValue(record.Usage), model.IntValue(record.Selector), model.IntValue(record.Dtype), record.Data)
+	default:
+		return record.Data
+	}
+}
+
+// quoteTXT quotes data as one or more character-strings of at most 255 bytes.
+func quoteTXT(data string) string {
+	strs := SplitTXT(data)
+	if len(strs) == 1 {
+		return quote(data)
+	}
+
+	parts := make([]string, len(strs))
+	for i, str := range strs {
+		parts[i] = quote(str)
+	}
+
+	return "( " + strings.Join(parts, "\n\t\t\t\t") + " )"
+}
+
+// SplitTXT splits data into character-strings of at most 255 bytes, without
+// splitting a UTF-8 encoded character.
+func SplitTXT(data string) []string {
+	var strs []string
+	for len(data) > MaxStringLength {
+		end := MaxStringLength
+		for end > 0 && !utf8.RuneStart(data[end]) {
+			end--
+		}
+		if end == 0 {
+			end = MaxStringLength
+		}
+		strs = append(strs, data[:end])
+		data = data[end:]
+	}
+	return append(strs, data)
+}
+
+func quote(value string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == '"' || c == '\\':
+			quoted.WriteByte('\\')
+			quoted.WriteByte(c)
+		case c < ' ' || c > '~':
+			fmt.Fprintf(&quoted, "\\%03d", c)
+		default:
+			quoted.WriteByte(c)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
+
+func fqdn(name string) string {
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+	return name + "."
+}
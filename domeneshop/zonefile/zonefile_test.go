@@ -0,0 +1,157 @@
+package zonefile_test
+
+import (
+	"reflect"
+	"strings"
+	"terraform-provider-domeneshop/domeneshop/model"
+	"terraform-provider-domeneshop/domeneshop/zonefile"
+	"testing"
+	"unicode/utf8"
+)
+
+const zone = `; Legacy zone for example.com
+$ORIGIN example.com.
+$TTL 1h
+@	IN	SOA	ns1.example.net. hostmaster.example.com. (
+		2020101301 ; serial
+		3600 900 1209600 300 )
+	IN	NS	ns1.hyp.net.
+	300	IN	A	192.0.2.1
+	IN	MX	10 mx1
+	IN	MX	20 mx2.example.org.
+www		CNAME	@
+_sip._tcp	IN	SRV	10 5 5060 sip
+@	CAA	0 issue "letsencrypt.org"
+txt	1d	TXT	"v=spf1 " "include:_spf.example.com -all"
+long	TXT	( "first part"
+		  " and \"second\" part" )
+
+$ORIGIN sub.example.com.
+host	A	192.0.2.2
+`
+
+func TestParse(t *testing.T) {
+	records, err := zonefile.Parse(strings.NewReader(zone), "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zero := 0
+	expected := []model.DnsRecord{
+		{Host: "@", Ttl: 3600, Type: "NS", Data: "ns1.hyp.net"},
+		{Host: "@", Ttl: 300, Type: "A", Data: "192.0.2.1"},
+		{Host: "@", Ttl: 3600, Type: "MX", Data: "mx1.example.com", Priority: "10"},
+		{Host: "@", Ttl: 3600, Type: "MX", Data: "mx2.example.org", Priority: "20"},
+		{Host: "www", Ttl: 3600, Type: "CNAME", Data: "example.com"},
+		{Host: "_sip._tcp", Ttl: 3600, Type: "SRV", Data: "sip.example.com", Priority: "10", Weight: 5, Port: 5060},
+		{Host: "@", Ttl: 3600, Type: "CAA", Data: "letsencrypt.org", Flags: &zero, Tag: &zero},
+		{Host: "txt", Ttl: 86400, Type: "TXT", Data: "v=spf1 include:_spf.example.com -all"},
+		{Host: "long", Ttl: 3600, Type: "TXT", Data: `first part and "second" part`},
+		{Host: "host.sub", Ttl: 3600, Type: "A", Data: "192.0.2.2"},
+	}
+
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("unexpected records:\n%+v\nexpected:\n%+v", records, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := map[string]string{
+		"outside of domain":  "www.example.org. A 192.0.2.1",
+		"unbalanced":         "@ TXT ( \"open\"",
+		"unterminated":       "@ TXT \"open",
+		"unsupported":        "$INCLUDE other.zone",
+		"missing owner":      "\tA 192.0.2.1",
+		"wrong field count":  "@ MX mx.example.com",
+		"unsupported record": "@ HINFO cpu os",
+		"short TTL":          "@ 30 A 192.0.2.1",
+		"odd TTL":            "@ 90 A 192.0.2.1",
+		"long $TTL":          "$TTL 2w\n@ A 192.0.2.1",
+	}
+
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			if _, err := zonefile.Parse(strings.NewReader(content), "example.com"); err == nil {
+				t.Errorf("expected an error parsing %q", content)
+			}
+		})
+	}
+}
+
+func TestParseTTLLine(t *testing.T) {
+	content := "www 300 A 192.0.2.1\nmail 1h1s A 192.0.2.2\n"
+	_, err := zonefile.Parse(strings.NewReader(content), "example.com")
+	if err == nil || !strings.Contains(err.Error(), "line 2: A record mail: TTL must be a multiple of 60 seconds, got 3601") {
+		t.Errorf("expected the TTL on line 2 to be rejected, got %v", err)
+	}
+}
+
+func TestRenderRoundTrip(t *testing.T) {
+	flags, tag := 128, 2
+	keyTag, alg, digest := 2371, 13, 2
+	usage, selector, dtype := 3, 1, 1
+	records := []model.DnsRecord{
+		{Host: "www", Ttl: 300, Type: "A", Data: "192.0.2.1"},
+		{Host: "@", Ttl: 3600, Type: "MX", Data: "mx.example.com", Priority: "10"},
+		{Host: "@", Ttl: 3600, Type: "CAA", Data: "mailto:security@example.com", Flags: &flags, Tag: &tag},
+		{Host: "sub", Ttl: 3600, Type: "DS", Data: "1F987CC6583E92DF0890718C42A7AA5E", Tag: &keyTag, Alg: &alg, Digest: &digest},
+		{Host: "_443._tcp.www", Ttl: 3600, Type: "TLSA", Data: "d2abde240d7cd3ee", Usage: &usage, Selector: &selector, Dtype: &dtype},
+		{Host: "_sip._tcp", Ttl: 3600, Type: "SRV", Data: "sip.example.com", Priority: "10", Weight: 0, Port: 5060},
+		{Host: "txt", Ttl: 3600, Type: "TXT", Data: strings.Repeat("a", 300) + ` "quoted"`},
+		{Host: "utf8", Ttl: 3600, Type: "TXT", Data: strings.Repeat("æøå", 100)},
+	}
+
+	rendered := zonefile.Render("example.com", records)
+
+	parsed, err := zonefile.Parse(strings.NewReader(rendered), "example.com")
+	if err != nil {
+		t.Fatalf("parsing rendered zone: %v\n%s", err, rendered)
+	}
+	if len(parsed) != len(records) {
+		t.Fatalf("expected %d records, got %d:\n%s", len(records), len(parsed), rendered)
+	}
+
+	for _, record := range records {
+		found := false
+		for _, other := range parsed {
+			if reflect.DeepEqual(record, other) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("record %+v did not survive the round trip:\n%s", record, rendered)
+		}
+	}
+
+	if !strings.HasPrefix(rendered, "$ORIGIN example.com.\n") {
+		t.Errorf("expected the zone to start with $ORIGIN, got:\n%s", rendered)
+	}
+}
+
+func TestSplitTXT(t *testing.T) {
+	cases := []struct {
+		data    string
+		strings int
+	}{
+		{"", 1},
+		{strings.Repeat("a", 255), 1},
+		{strings.Repeat("a", 256), 2},
+		{strings.Repeat("æøå", 100), 3},
+		{"a" + strings.Repeat("€", 100), 2},
+	}
+
+	for _, tc := range cases {
+		strs := zonefile.SplitTXT(tc.data)
+		if len(strs) != tc.strings {
+			t.Errorf("expected %d strings, got %d", tc.strings, len(strs))
+		}
+		if joined := strings.Join(strs, ""); joined != tc.data {
+			t.Errorf("strings don't join back to the data: %q", joined)
+		}
+		for _, str := range strs {
+			if len(str) > zonefile.MaxStringLength || !utf8.ValidString(str) {
+				t.Errorf("invalid string %q of %d bytes", str, len(str))
+			}
+		}
+	}
+}